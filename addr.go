@@ -0,0 +1,26 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-i2p/go-i2p/lib/naming"
+	"io"
+)
+
+// go-i2p addr <address>
+// print every form of an address given as a .b32.i2p address, base64 destination or hex hash
+func addrCommand(args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New("usage: go-i2p addr <b32 address | base64 destination | hex hash>")
+	}
+	address, err := naming.ParseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "hash: %s\n", address.Hex())
+	fmt.Fprintf(out, "b32:  %s\n", address.Base32())
+	if b64 := address.Base64(); b64 != "" {
+		fmt.Fprintf(out, "b64:  %s\n", b64)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/naming"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAddrCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	data := make([]byte, 128+256)
+	data[0] = 0x07
+	dest := common.Destination(append(data, 0x00, 0x00, 0x00))
+	hash := common.HashData(dest)
+	hash_line := "hash: " + hex.EncodeToString(hash[:]) + "\n"
+	b32_line := "b32:  " + dest.Base32Address() + "\n"
+	b64_line := "b64:  " + dest.Base64() + "\n"
+
+	tests := []struct {
+		name string
+		args []string
+		out  string
+		err  error
+	}{
+		{"b32", []string{dest.Base32Address()}, hash_line + b32_line, nil},
+		{"base64", []string{dest.Base64()}, hash_line + b32_line + b64_line, nil},
+		{"hash", []string{hex.EncodeToString(hash[:])}, hash_line + b32_line, nil},
+		{"invalid", []string{"example.i2p"}, "", naming.ERR_ADDRESS_UNRECOGNIZED},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		err := addrCommand(test.args, &buf)
+		assert.Equal(test.err, err, test.name)
+		assert.Equal(test.out, buf.String(), test.name)
+	}
+}
+
+func TestAddrCommandRequiresOneAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, args := range [][]string{{}, {"a", "b"}} {
+		var buf bytes.Buffer
+		err := addrCommand(args, &buf)
+		if assert.NotNil(err) {
+			assert.Contains(err.Error(), "usage: go-i2p addr")
+		}
+		assert.Empty(buf.String())
+	}
+}
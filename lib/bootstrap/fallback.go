@@ -0,0 +1,33 @@
+package bootstrap
+
+import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	log "github.com/sirupsen/logrus"
+)
+
+var ERR_ALL_BOOTSTRAPS_FAILED = errors.New("all bootstrap methods failed")
+
+// tries a list of Bootstraps in order, using the first one that yields peers
+type fallbackBootstrap []Bootstrap
+
+// create a Bootstrap that tries each of the given Bootstraps in order until one succeeds
+// i.e. Fallback(primary, secondary) to only use secondary when primary fails
+func Fallback(bootstraps ...Bootstrap) Bootstrap {
+	return fallbackBootstrap(bootstraps)
+}
+
+func (f fallbackBootstrap) GetPeers(n int) (chan []common.RouterInfo, error) {
+	for idx, b := range f {
+		chnl, err := b.GetPeers(n)
+		if err == nil {
+			return chnl, nil
+		}
+		log.WithFields(log.Fields{
+			"at":     "(fallbackBootstrap) GetPeers",
+			"index":  idx,
+			"reason": err.Error(),
+		}).Warn("bootstrap failed, trying next")
+	}
+	return nil, ERR_ALL_BOOTSTRAPS_FAILED
+}
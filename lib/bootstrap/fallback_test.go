@@ -0,0 +1,42 @@
+package bootstrap
+
+import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/internal/fixture"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type failingBootstrap struct{}
+
+func (failingBootstrap) GetPeers(n int) (chan []common.RouterInfo, error) {
+	return nil, errors.New("reseed servers unreachable")
+}
+
+// a Bootstrap that always hands out the same RouterInfos
+type staticBootstrap []common.RouterInfo
+
+func (b staticBootstrap) GetPeers(n int) (chan []common.RouterInfo, error) {
+	chnl := make(chan []common.RouterInfo, 1)
+	chnl <- b
+	return chnl, nil
+}
+
+func TestFallbackUsesNextBootstrapWhenReseedFails(t *testing.T) {
+	assert := assert.New(t)
+
+	ri := fixture.RouterInfo{}.Build()
+	b := Fallback(failingBootstrap{}, staticBootstrap{ri})
+	chnl, err := b.GetPeers(1)
+	assert.Nil(err)
+	assert.Equal([]common.RouterInfo{ri}, <-chnl)
+}
+
+func TestFallbackFailsWhenAllBootstrapsFail(t *testing.T) {
+	assert := assert.New(t)
+
+	b := Fallback(failingBootstrap{}, failingBootstrap{})
+	_, err := b.GetPeers(1)
+	assert.Equal(ERR_ALL_BOOTSTRAPS_FAILED, err)
+}
@@ -0,0 +1,179 @@
+package bootstrap
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/config"
+	"github.com/go-i2p/go-i2p/lib/i2np"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// name of the su3 file fetched from each reseed server
+const RESEED_SU3_FILENAME = "i2pseeds.su3"
+
+// user agent expected by reseed servers
+const RESEED_USER_AGENT = "Wget/1.11.4"
+
+// largest reseed su3 we will download
+const RESEED_MAX_SU3_SIZE = 4 * 1024 * 1024
+
+var ERR_RESEED_FAILED = errors.New("could not reseed from any reseed server")
+var ERR_RESEED_BAD_CONTENT = errors.New("reseed su3 does not contain zipped reseed data")
+var ERR_RESEED_TOO_LARGE = errors.New("reseed data is larger than allowed")
+
+// bootstraps by fetching an su3 of RouterInfos from the configured reseed servers
+type ReseedBootstrap struct {
+	// reseed servers to try in order
+	Servers []*config.ReseedConfig
+	// http client used to fetch the su3
+	// set this to reseed through a proxy, e.g. with a SOCKS dialer or over an i2p tunnel
+	// if nil a direct https client is used
+	Client *http.Client
+}
+
+// create a ReseedBootstrap for the reseed servers in this config using a direct https client
+func NewReseedBootstrap(conf *config.BootstrapConfig) *ReseedBootstrap {
+	return &ReseedBootstrap{
+		Servers: conf.ReseedServers,
+	}
+}
+
+// direct https client used when no client is set
+// keeps the default transport's proxy from environment and dial and tls timeouts
+func defaultReseedClient() *http.Client {
+	return &http.Client{
+		Timeout:   time.Minute,
+		Transport: http.DefaultTransport.(*http.Transport).Clone(),
+	}
+}
+
+func (r *ReseedBootstrap) client() *http.Client {
+	if r.Client == nil {
+		return defaultReseedClient()
+	}
+	return r.Client
+}
+
+// get at most n RouterInfos from the first reseed server that answers
+func (r *ReseedBootstrap) GetPeers(n int) (chan []common.RouterInfo, error) {
+	for _, server := range r.Servers {
+		peers, err := r.reseed(server)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"at":     "(ReseedBootstrap) GetPeers",
+				"url":    server.Url,
+				"reason": err.Error(),
+			}).Warn("reseed failed")
+			continue
+		}
+		if len(peers) == 0 {
+			continue
+		}
+		if n > 0 && len(peers) > n {
+			peers = peers[:n]
+		}
+		chnl := make(chan []common.RouterInfo, 1)
+		chnl <- peers
+		return chnl, nil
+	}
+	return nil, ERR_RESEED_FAILED
+}
+
+// fetch the su3 from one reseed server, check it is signed by the server's configured
+// signing key and unpack the RouterInfos in it whose signatures verify
+func (r *ReseedBootstrap) reseed(server *config.ReseedConfig) (peers []common.RouterInfo, err error) {
+	signing_key, err := server.SigningKey()
+	if err != nil {
+		return
+	}
+	url := server.Url
+	if !strings.HasSuffix(url, "/") {
+		url += "/"
+	}
+	var req *http.Request
+	req, err = http.NewRequest("GET", url+RESEED_SU3_FILENAME, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("User-Agent", RESEED_USER_AGENT)
+	var resp *http.Response
+	resp, err = r.client().Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = errors.New("reseed server returned " + resp.Status)
+		return
+	}
+	var data []byte
+	data, err = readLimited(resp.Body, RESEED_MAX_SU3_SIZE)
+	if err != nil {
+		return
+	}
+	var su3 config.SU3
+	su3, err = config.ReadSU3(data)
+	if err != nil {
+		return
+	}
+	if err = su3.Verify(signing_key); err != nil {
+		return
+	}
+	if su3.CheckContent(config.SU3_CONTENT_TYPE_RESEED_DATA, config.SU3_FILE_TYPE_ZIP) != nil {
+		err = ERR_RESEED_BAD_CONTENT
+		return
+	}
+	peers, err = readReseedZip(su3.Content)
+	return
+}
+
+// read the routerInfo-*.dat files out of zipped reseed data, dropping any that do not verify
+func readReseedZip(content []byte) (peers []common.RouterInfo, err error) {
+	var archive *zip.Reader
+	archive, err = zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return
+	}
+	for _, file := range archive.File {
+		if !strings.HasPrefix(file.Name, "routerInfo-") || !strings.HasSuffix(file.Name, ".dat") {
+			continue
+		}
+		rc, ferr := file.Open()
+		if ferr != nil {
+			continue
+		}
+		data, ferr := readLimited(rc, i2np.DATABASE_STORE_MAX_ROUTER_INFO_SIZE)
+		rc.Close()
+		if ferr != nil {
+			continue
+		}
+		ri := common.RouterInfo(data)
+		if ferr = ri.Verify(); ferr != nil {
+			log.WithFields(log.Fields{
+				"at":     "readReseedZip",
+				"file":   file.Name,
+				"reason": ferr.Error(),
+			}).Warn("skipping invalid reseed router info")
+			continue
+		}
+		peers = append(peers, ri)
+	}
+	return
+}
+
+// read all of r, returning ERR_RESEED_TOO_LARGE if it holds more than limit bytes
+func readLimited(r io.Reader, limit int) (data []byte, err error) {
+	data, err = ioutil.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err == nil && len(data) > limit {
+		data = nil
+		err = ERR_RESEED_TOO_LARGE
+	}
+	return
+}
@@ -0,0 +1,185 @@
+package bootstrap
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/config"
+	"github.com/go-i2p/go-i2p/lib/internal/fixture"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// a reseed signing key and the server config that pins it
+func buildReseedSigner(t *testing.T, url string) (*ecdsa.PrivateKey, *config.ReseedConfig) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test@mail.i2p"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return priv, &config.ReseedConfig{
+		Url:            url,
+		SU3Fingerprint: config.SU3KeyFingerprint(cert),
+		SU3Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+}
+
+func buildReseedSU3(t *testing.T, priv *ecdsa.PrivateKey, ris map[string][]byte) []byte {
+	buff := new(bytes.Buffer)
+	archive := zip.NewWriter(buff)
+	for name, data := range ris {
+		w, _ := archive.Create(name)
+		w.Write(data)
+	}
+	archive.Close()
+	content := buff.Bytes()
+
+	version := make([]byte, 16)
+	copy(version, "1")
+	signer_id := []byte("test@mail.i2p")
+
+	su3 := []byte("I2Psu3")
+	su3 = append(su3, 0x00, 0x00)
+	su3 = append(su3, 0x00, 0x01)
+	su3 = append(su3, 0x00, 64)
+	su3 = append(su3, 0x00, byte(len(version)))
+	su3 = append(su3, 0x00, byte(len(signer_id)))
+	content_length := make([]byte, 8)
+	binary.BigEndian.PutUint64(content_length, uint64(len(content)))
+	su3 = append(su3, content_length...)
+	su3 = append(su3, 0x00, 0x00)
+	su3 = append(su3, 0x00, 0x03)
+	su3 = append(su3, make([]byte, 12)...)
+	su3 = append(su3, version...)
+	su3 = append(su3, signer_id...)
+	su3 = append(su3, content...)
+
+	digest := sha256.Sum256(su3)
+	r, s, err := ecdsa.Sign(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return append(su3, signature...)
+}
+
+// serve su3 as the reseed file of any host through a proxy and return a client using it
+func serveReseed(t *testing.T, su3 []byte, proxied *bool) (*httptest.Server, *http.Client) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Host == "reseed.example.i2p" && req.URL.Path == "/"+RESEED_SU3_FILENAME {
+			*proxied = true
+			w.Write(su3)
+			return
+		}
+		http.NotFound(w, req)
+	}))
+	proxy_url, _ := url.Parse(proxy.URL)
+	return proxy, &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyURL(proxy_url)},
+	}
+}
+
+func TestReseedThroughProxyClient(t *testing.T) {
+	assert := assert.New(t)
+
+	ri := fixture.RouterInfo{}.Build()
+	tampered := append(common.RouterInfo{}, ri...)
+	tampered[400] ^= 0xff
+	priv, server := buildReseedSigner(t, "http://reseed.example.i2p")
+	su3 := buildReseedSU3(t, priv, map[string][]byte{
+		"routerInfo-AAAA.dat": ri,
+		"routerInfo-BBBB.dat": tampered,
+		"README.txt":          []byte("not a router info"),
+	})
+	proxied := false
+	proxy, client := serveReseed(t, su3, &proxied)
+	defer proxy.Close()
+
+	reseed := NewReseedBootstrap(&config.BootstrapConfig{
+		ReseedServers: []*config.ReseedConfig{server},
+	})
+	reseed.Client = client
+	chnl, err := reseed.GetPeers(0)
+	assert.Nil(err)
+	assert.True(proxied, "reseed request did not go through the proxy")
+	assert.Equal([]common.RouterInfo{ri}, <-chnl, "router infos that do not verify should be dropped")
+}
+
+func TestReseedRejectsSU3NotSignedByPinnedKey(t *testing.T) {
+	assert := assert.New(t)
+
+	_, server := buildReseedSigner(t, "http://reseed.example.i2p")
+	other, _ := buildReseedSigner(t, "http://reseed.example.i2p")
+	su3 := buildReseedSU3(t, other, map[string][]byte{
+		"routerInfo-AAAA.dat": fixture.RouterInfo{}.Build(),
+	})
+	proxied := false
+	proxy, client := serveReseed(t, su3, &proxied)
+	defer proxy.Close()
+
+	reseed := &ReseedBootstrap{Servers: []*config.ReseedConfig{server}, Client: client}
+	_, err := reseed.reseed(server)
+	assert.Equal(config.ERR_SU3_SIGNATURE_INVALID, err)
+
+	// change the first byte of the pinned fingerprint, whatever it was
+	wrong_prefix := "00"
+	if strings.HasPrefix(server.SU3Fingerprint, wrong_prefix) {
+		wrong_prefix = "ff"
+	}
+	server.SU3Fingerprint = wrong_prefix + server.SU3Fingerprint[2:]
+	_, err = reseed.reseed(server)
+	assert.Equal(config.ERR_SU3_FINGERPRINT_MISMATCH, err)
+}
+
+func TestReadLimitedRejectsOversizedData(t *testing.T) {
+	assert := assert.New(t)
+
+	data, err := readLimited(bytes.NewReader(make([]byte, 11)), 10)
+	assert.Nil(data)
+	assert.Equal(ERR_RESEED_TOO_LARGE, err)
+	data, err = readLimited(bytes.NewReader(make([]byte, 10)), 10)
+	assert.Nil(err)
+	assert.Len(data, 10)
+}
+
+func TestReseedFailsWhenNoServerAnswers(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, reseed_server := buildReseedSigner(t, server.URL)
+	reseed := NewReseedBootstrap(&config.BootstrapConfig{
+		ReseedServers: []*config.ReseedConfig{reseed_server},
+	})
+	reseed.Client = server.Client()
+	chnl, err := reseed.GetPeers(0)
+	assert.Nil(chnl)
+	assert.Equal(ERR_RESEED_FAILED, err)
+}
@@ -46,6 +46,12 @@ const (
 	CERT_MIN_SIZE = 3
 )
 
+// Smallest payload of a KEY Certificate, the signing and crypto key types
+const CERT_KEY_MIN_LENGTH = 4
+
+var ERR_CERTIFICATE_LENGTH_IMPLAUSIBLE = errors.New("certificate length is not plausible for its type")
+var ERR_CERTIFICATE_TRAILING_DATA = errors.New("certificate contains data beyond length")
+
 type Certificate []byte
 
 //
@@ -81,22 +87,22 @@ func (certificate Certificate) Length() (length int, err error) {
 	length = Integer(certificate[1:CERT_MIN_SIZE])
 	inferred_len := length + CERT_MIN_SIZE
 	if inferred_len > cert_len {
-		log.WithFields(log.Fields{
+		parseLog.Warn(log.Fields{
 			"at":                       "(Certificate) Length",
 			"certificate_bytes_length": cert_len,
 			"certificate_length_field": length,
 			"expected_bytes_length":    inferred_len,
 			"reason":                   "data shorter than specified",
-		}).Warn("certificate format warning")
+		}, "certificate format warning")
 		err = errors.New("certificate parsing warning: certificate data is shorter than specified by length")
 	} else if cert_len > inferred_len {
-		log.WithFields(log.Fields{
+		parseLog.Warn(log.Fields{
 			"at":                       "(Certificate) Length",
 			"certificate_bytes_length": cert_len,
 			"certificate_length_field": length,
 			"expected_bytes_length":    inferred_len,
 			"reason":                   "data longer than expected",
-		}).Warn("certificate format warning")
+		}, "certificate format warning")
 		err = errors.New("certificate parsing warning: certificate contains data beyond length")
 	}
 	return
@@ -135,5 +141,74 @@ func ReadCertificate(data []byte) (certificate Certificate, remainder []byte, er
 		remainder = data[length+CERT_MIN_SIZE:]
 		err = nil
 	}
+	if err == nil {
+		err = checkCertificateLength(certificate, length)
+	}
+	return
+}
+
+//
+// Read a Certificate that is expected to make up all of data, such as one being verified on
+// its own, returning ERR_CERTIFICATE_TRAILING_DATA if there are bytes beyond its length.
+// Use ReadCertificate for a Certificate embedded in a larger structure.
+//
+func ReadCertificateStrict(data []byte) (certificate Certificate, err error) {
+	certificate, remainder, err := ReadCertificate(data)
+	if err == nil && len(remainder) > 0 {
+		log.WithFields(log.Fields{
+			"at":                    "ReadCertificateStrict",
+			"certificate_length":    len(certificate),
+			"trailing_bytes_length": len(remainder),
+			"reason":                "data beyond length",
+		}).Error("invalid certificate")
+		err = ERR_CERTIFICATE_TRAILING_DATA
+	}
 	return
 }
+
+//
+// Check that a Certificate's length field is plausible for its type, returning
+// ERR_CERTIFICATE_LENGTH_IMPLAUSIBLE for a NULL or HIDDEN Certificate with a payload
+// or a KEY Certificate too short to hold its key types.
+//
+func checkCertificateLength(certificate Certificate, length int) (err error) {
+	cert_type, _ := certificate.Type()
+	implausible := false
+	switch cert_type {
+	case CERT_NULL, CERT_HIDDEN:
+		implausible = length != 0
+	case CERT_KEY:
+		implausible = length < CERT_KEY_MIN_LENGTH
+	}
+	if implausible {
+		log.WithFields(log.Fields{
+			"at":                       "ReadCertificate",
+			"certificate_type":         cert_type,
+			"certificate_length_field": length,
+			"reason":                   "length implausible for certificate type",
+		}).Error("invalid certificate")
+		err = ERR_CERTIFICATE_LENGTH_IMPLAUSIBLE
+	}
+	return
+}
+
+//
+// Read a Certificate from a slice of bytes, returning the Certificate, the total number of
+// bytes it occupies (CERT_MIN_SIZE + its length field), any extra data on the end of the
+// slice and any errors if a valid Certificate could not be read.
+//
+func ReadCertificateWithLength(data []byte) (certificate Certificate, length int, remainder []byte, err error) {
+	certificate, remainder, err = ReadCertificate(data)
+	if err != nil {
+		return
+	}
+	length = len(certificate)
+	return
+}
+
+//
+// Return the Certificate in its wire form.
+//
+func (certificate Certificate) Bytes() []byte {
+	return []byte(certificate)
+}
@@ -101,7 +101,7 @@ func TestCertificateDataWhenTooShort(t *testing.T) {
 func TestReadCertificateWithCorrectData(t *testing.T) {
 	assert := assert.New(t)
 
-	bytes := []byte{0x00, 0x00, 0x02, 0xff, 0xff}
+	bytes := []byte{0x01, 0x00, 0x02, 0xff, 0xff}
 	cert, remainder, err := ReadCertificate(bytes)
 
 	assert.Equal(len(cert), 5, "ReadCertificate() did not return correct amount of data for valid certificate")
@@ -125,7 +125,7 @@ func TestReadCertificateWithDataTooShort(t *testing.T) {
 func TestReadCertificateWithRemainder(t *testing.T) {
 	assert := assert.New(t)
 
-	bytes := []byte{0x00, 0x00, 0x02, 0xff, 0xff, 0x01}
+	bytes := []byte{0x01, 0x00, 0x02, 0xff, 0xff, 0x01}
 	cert, remainder, err := ReadCertificate(bytes)
 
 	assert.Equal(len(cert), 5, "ReadCertificate() did not return correct amount of data for certificate with extra data")
@@ -146,3 +146,89 @@ func TestReadCertificateWithInvalidLength(t *testing.T) {
 		assert.Equal("error parsing certificate length: certificate is too short", err.Error(), "correct error message should be returned")
 	}
 }
+
+func TestReadCertificateWithLengthOfKeyCertificate(t *testing.T) {
+	assert := assert.New(t)
+
+	bytes := []byte{0x05, 0x00, 0x04, 0x00, 0x07, 0x00, 0x00, 0xaa, 0xbb}
+	cert, length, remainder, err := ReadCertificateWithLength(bytes)
+
+	assert.Nil(err, "ReadCertificateWithLength() should not return an error with valid data")
+	cert_len, _ := cert.Length()
+	assert.Equal(CERT_MIN_SIZE+cert_len, length, "ReadCertificateWithLength() did not report 3 + CertLen bytes consumed")
+	assert.Equal(7, length)
+	assert.Equal([]byte{0xaa, 0xbb}, remainder, "ReadCertificateWithLength() did not return correct remainder")
+}
+
+func TestReadCertificateWithLengthWithDataTooShort(t *testing.T) {
+	assert := assert.New(t)
+
+	bytes := []byte{0x05, 0x00, 0x04, 0x00, 0x07}
+	_, length, remainder, err := ReadCertificateWithLength(bytes)
+
+	assert.Equal(0, length, "ReadCertificateWithLength() should not report bytes consumed for an incomplete certificate")
+	assert.Equal(0, len(remainder))
+	if assert.NotNil(err) {
+		assert.Equal("certificate parsing warning: certificate data is shorter than specified by length", err.Error(), "correct error message should be returned")
+	}
+}
+
+func TestNilCertificateReportsErrorsWithoutPanic(t *testing.T) {
+	assert := assert.New(t)
+
+	var certificate Certificate
+	_, err := certificate.Type()
+	assert.NotNil(err)
+	_, err = certificate.Length()
+	assert.NotNil(err)
+	data, err := certificate.Data()
+	assert.NotNil(err)
+	assert.Empty(data)
+}
+
+func TestCertificateBytesRoundTrips(t *testing.T) {
+	assert := assert.New(t)
+
+	certificate, _, err := ReadCertificate([]byte{0x05, 0x00, 0x04, 0x00, 0x07, 0x00, 0x04})
+	assert.Nil(err)
+	again, remainder, err := ReadCertificate(certificate.Bytes())
+	assert.Nil(err)
+	assert.Empty(remainder)
+	assert.Equal(certificate, again)
+}
+
+func TestReadCertificateRejectsImplausibleLengths(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, err := ReadCertificate([]byte{0x00, 0x00, 0x02, 0xff, 0xff})
+	assert.Equal(ERR_CERTIFICATE_LENGTH_IMPLAUSIBLE, err, "a NULL certificate has no payload")
+	_, _, err = ReadCertificate([]byte{0x05, 0x00, 0x02, 0x00, 0x07, 0xaa})
+	assert.Equal(ERR_CERTIFICATE_LENGTH_IMPLAUSIBLE, err, "a KEY certificate holds two 2 byte key types")
+
+	_, remainder, err := ReadCertificate([]byte{0x00, 0x00, 0x00, 0xaa})
+	assert.Nil(err)
+	assert.Equal([]byte{0xaa}, remainder)
+}
+
+func TestReadCertificateStrictRejectsTrailingData(t *testing.T) {
+	assert := assert.New(t)
+
+	bytes := []byte{0x01, 0x00, 0x02, 0xff, 0xff, 0x01}
+	_, remainder, err := ReadCertificate(bytes)
+	assert.Nil(err, "lenient mode should return trailing data as a remainder")
+	assert.Equal([]byte{0x01}, remainder)
+
+	_, err = ReadCertificateStrict(bytes)
+	assert.Equal(ERR_CERTIFICATE_TRAILING_DATA, err)
+}
+
+func TestReadCertificateStrictWithExactData(t *testing.T) {
+	assert := assert.New(t)
+
+	cert, err := ReadCertificateStrict([]byte{0x01, 0x00, 0x02, 0xff, 0xff})
+	assert.Nil(err)
+	assert.Equal(Certificate([]byte{0x01, 0x00, 0x02, 0xff, 0xff}), cert)
+
+	_, err = ReadCertificateStrict([]byte{0x00, 0x00, 0x02, 0xff})
+	assert.NotNil(err, "strict mode should still reject short data")
+}
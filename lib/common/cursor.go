@@ -0,0 +1,135 @@
+package common
+
+import (
+	"errors"
+	log "github.com/sirupsen/logrus"
+)
+
+var ERR_CURSOR_NOT_ENOUGH_DATA = errors.New("error reading structure: not enough data")
+
+//
+// A Cursor reads a sequence of concatenated structures from a slice of bytes, keeping
+// track of how far it has read so that callers do not have to pass remainders along
+// by hand.  A failed read does not advance the Cursor.
+//
+type Cursor struct {
+	data   []byte
+	offset int
+}
+
+//
+// Create a Cursor positioned at the start of data.
+//
+func NewCursor(data []byte) *Cursor {
+	return &Cursor{data: data}
+}
+
+//
+// Return how many bytes the Cursor has read, for use when reporting where a
+// structure was malformed.
+//
+func (cursor *Cursor) Offset() int {
+	return cursor.offset
+}
+
+//
+// Return the bytes the Cursor has not yet read.
+//
+func (cursor *Cursor) Remaining() []byte {
+	return cursor.data[cursor.offset:]
+}
+
+//
+// Read the next n bytes, returning ERR_CURSOR_NOT_ENOUGH_DATA if fewer remain.
+//
+func (cursor *Cursor) ReadBytes(n int) (data []byte, err error) {
+	remaining := len(cursor.data) - cursor.offset
+	if n < 0 || n > remaining {
+		log.WithFields(log.Fields{
+			"at":           "(Cursor) ReadBytes",
+			"offset":       cursor.offset,
+			"data_len":     remaining,
+			"required_len": n,
+			"reason":       "not enough data",
+		}).Error("error reading structure")
+		err = ERR_CURSOR_NOT_ENOUGH_DATA
+		return
+	}
+	data = cursor.data[cursor.offset : cursor.offset+n]
+	cursor.offset += n
+	return
+}
+
+//
+// Read the next n bytes as a big-endian Integer.
+//
+func (cursor *Cursor) ReadInteger(n int) (value int, err error) {
+	data, err := cursor.ReadBytes(n)
+	if err == nil {
+		value = Integer(data)
+	}
+	return
+}
+
+//
+// Read the next 8 bytes as a Date.
+//
+func (cursor *Cursor) ReadDate() (date Date, err error) {
+	data, err := cursor.ReadBytes(len(date))
+	if err == nil {
+		copy(date[:], data)
+	}
+	return
+}
+
+//
+// Read the next Certificate, ignoring any data that follows it.
+//
+func (cursor *Cursor) ReadCertificate() (certificate Certificate, err error) {
+	certificate, remainder, err := ReadCertificate(cursor.Remaining())
+	if err == nil {
+		cursor.skipTo(remainder)
+	}
+	return
+}
+
+//
+// Read the next Mapping.
+//
+func (cursor *Cursor) ReadMapping() (mapping Mapping, err error) {
+	mapping, remainder, err := ReadMapping(cursor.Remaining())
+	if err == nil {
+		cursor.skipTo(remainder)
+	}
+	return
+}
+
+//
+// Read the next RouterIdentity.
+//
+func (cursor *Cursor) ReadRouterIdentity() (router_identity RouterIdentity, err error) {
+	router_identity, remainder, err := ReadRouterIdentity(cursor.Remaining())
+	if err == nil {
+		cursor.skipTo(remainder)
+	}
+	return
+}
+
+//
+// Read the next RouterAddress.
+//
+func (cursor *Cursor) ReadRouterAddress() (router_address RouterAddress, err error) {
+	router_address, remainder, err := ReadRouterAddress(cursor.Remaining())
+	if err == nil {
+		cursor.skipTo(remainder)
+	}
+	return
+}
+
+//
+// Move the Cursor to the start of remainder, the unread tail of its data returned
+// by one of the Read functions.
+//
+func (cursor *Cursor) skipTo(remainder []byte) {
+	cursor.offset = len(cursor.data) - len(remainder)
+}
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCursorReadsConcatenatedStructures(t *testing.T) {
+	assert := assert.New(t)
+
+	mapping, _ := GoMapToMapping(map[string]string{"a": "b"})
+	data := []byte{0x05, 0x00, 0x04, 0x00, 0x07, 0x00, 0x00}
+	data = append(data, 0x00, 0x00, 0x01, 0x02)
+	data = append(data, mapping...)
+	data = append(data, 0xff)
+
+	cursor := NewCursor(data)
+	certificate, err := cursor.ReadCertificate()
+	assert.Nil(err)
+	assert.Equal(Certificate(data[:7]), certificate)
+	assert.Equal(7, cursor.Offset())
+
+	value, err := cursor.ReadInteger(4)
+	assert.Nil(err)
+	assert.Equal(0x0102, value)
+
+	read_mapping, err := cursor.ReadMapping()
+	assert.Nil(err)
+	assert.Equal(mapping, read_mapping)
+
+	last, err := cursor.ReadBytes(1)
+	assert.Nil(err)
+	assert.Equal([]byte{0xff}, last)
+	assert.Equal(len(data), cursor.Offset())
+	assert.Empty(cursor.Remaining())
+}
+
+func TestCursorDoesNotAdvancePastEnd(t *testing.T) {
+	assert := assert.New(t)
+
+	cursor := NewCursor([]byte{0x01, 0x02, 0x03})
+	cursor.ReadBytes(2)
+	_, err := cursor.ReadInteger(2)
+	assert.Equal(ERR_CURSOR_NOT_ENOUGH_DATA, err)
+	assert.Equal(2, cursor.Offset(), "failed read should leave the cursor where it was")
+
+	_, err = cursor.ReadMapping()
+	assert.Equal(ERR_MAPPING_TOO_SHORT, err)
+	assert.Equal(2, cursor.Offset())
+}
@@ -25,6 +25,10 @@ func (destination Destination) PublicKey() (crypto.PublicKey, error) {
 	return KeysAndCert(destination).PublicKey()
 }
 
+func (destination Destination) EncryptionKey() (crypto.PublicKey, error) {
+	return KeysAndCert(destination).EncryptionKey()
+}
+
 func (destination Destination) SigningPublicKey() (crypto.SigningPublicKey, error) {
 	return KeysAndCert(destination).SigningPublicKey()
 }
@@ -43,7 +47,15 @@ func ReadDestination(data []byte) (destination Destination, remainder []byte, er
 // Generate the I2P base32 address for this Destination.
 //
 func (destination Destination) Base32Address() (str string) {
-	hash := crypto.SHA256(destination)
+	return DestinationBase32(destination)
+}
+
+//
+// Generate the I2P base32 address for the raw bytes of a Destination without parsing
+// or validating them, for logging and quick lookups.
+//
+func DestinationBase32(raw_dest []byte) (str string) {
+	hash := HashData(raw_dest)
 	str = strings.Trim(base32.EncodeToString(hash[:]), "=")
 	str = str + ".b32.i2p"
 	return
@@ -0,0 +1,16 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDestinationBase32MatchesParsedDestination(t *testing.T) {
+	assert := assert.New(t)
+
+	raw_dest := buildRouterIdentity()
+	destination, _, err := ReadDestination(append(append([]byte{}, raw_dest...), 0x01, 0x02))
+	assert.Nil(err)
+	assert.Equal(destination.Base32Address(), DestinationBase32(raw_dest))
+	assert.Len(DestinationBase32(raw_dest), 52+len(".b32.i2p"))
+}
@@ -0,0 +1,56 @@
+package common
+
+import (
+	"github.com/go-i2p/go-i2p/lib/util"
+	"time"
+)
+
+// How long after it was published a RouterInfo is considered expired
+const ROUTER_INFO_MAX_AGE = 27 * time.Hour
+
+//
+// Return true if the Date is before the current time of clock.
+//
+func (date Date) Expired(clock util.Clock) bool {
+	return date.Time().Before(clock.Now())
+}
+
+//
+// Return true if the Lease's end date has passed.
+//
+func (lease Lease) Expired(clock util.Clock) bool {
+	return lease.Date().Expired(clock)
+}
+
+//
+// Return true if every Lease in the LeaseSet has expired, or if the LeaseSet
+// cannot be parsed.
+//
+func (lease_set LeaseSet) Expired(clock util.Clock) bool {
+	newest, err := lease_set.NewestExpiration()
+	return err != nil || newest.Expired(clock)
+}
+
+//
+// Return true if the RouterAddress carries an expiration that has passed.
+// A zero expiration never expires.
+//
+func (router_address RouterAddress) Expired(clock util.Clock) bool {
+	expiration, err := router_address.Expiration()
+	if err != nil {
+		return true
+	}
+	return expiration != (Date{}) && expiration.Expired(clock)
+}
+
+//
+// Return true if the RouterInfo was published more than ROUTER_INFO_MAX_AGE ago,
+// or if its published date cannot be parsed.
+//
+func (router_info RouterInfo) Expired(clock util.Clock) bool {
+	published, err := router_info.Published()
+	if err != nil {
+		return true
+	}
+	return published.Time().Add(ROUTER_INFO_MAX_AGE).Before(clock.Now())
+}
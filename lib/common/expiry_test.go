@@ -0,0 +1,44 @@
+package common
+
+import (
+	"github.com/go-i2p/go-i2p/lib/util"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type fixedClock time.Time
+
+func (clock fixedClock) Now() time.Time {
+	return time.Time(clock)
+}
+
+func TestRouterInfoExpiredFollowsClock(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildRouterInfo(buildRouterIdentity(), nil, map[string]string{"caps": "L"})
+	published, _ := router_info.Published()
+	assert.False(router_info.Expired(fixedClock(published.Time().Add(time.Hour))))
+	assert.True(router_info.Expired(fixedClock(published.Time().Add(ROUTER_INFO_MAX_AGE+time.Second))))
+
+	skewed := util.OffsetClock{Clock: fixedClock(published.Time()), Offset: ROUTER_INFO_MAX_AGE + time.Minute}
+	assert.True(router_info.Expired(skewed), "a skew adjusted clock can make a fresh RouterInfo expired")
+}
+
+func TestLeaseExpiredFollowsClock(t *testing.T) {
+	assert := assert.New(t)
+
+	var lease Lease
+	copy(lease[LEASE_HASH_SIZE+LEASE_TUNNEL_ID_SIZE:], buildDate())
+	end := lease.Date().Time()
+	assert.False(lease.Expired(fixedClock(end.Add(-time.Second))))
+	assert.True(lease.Expired(fixedClock(end.Add(time.Second))))
+}
+
+func TestRouterAddressWithoutExpirationNeverExpires(t *testing.T) {
+	assert := assert.New(t)
+
+	router_address := buildRouterAddress("NTCP2")
+	assert.False(router_address.Expired(fixedClock(time.Now().Add(100 * 365 * 24 * time.Hour))))
+	assert.True(RouterAddress{}.Expired(util.SystemClock))
+}
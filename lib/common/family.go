@@ -0,0 +1,168 @@
+package common
+
+/*
+I2P Router Family
+https://geti2p.net/spec/family
+Accurate for version 0.9.24
+
+A router declares membership of a family with three RouterInfo options:
+
+family     :: the family name
+family.key :: $sigtype;$key
+              sigtype -> code of the family signing key type, as in Key Certificates
+              key     -> base64 of the family SigningPublicKey
+family.sig :: base64 of the Signature of the family name followed by the
+              32 byte hash of the RouterIdentity, made with the family key
+*/
+
+import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common/base64"
+	"github.com/go-i2p/go-i2p/lib/crypto"
+	log "github.com/sirupsen/logrus"
+	"strconv"
+	"strings"
+)
+
+// RouterInfo option keys used to declare a router family
+const (
+	FAMILY_OPTION_NAME = "family"
+	FAMILY_OPTION_KEY  = "family.key"
+	FAMILY_OPTION_SIG  = "family.sig"
+)
+
+var ERR_FAMILY_NOT_PRESENT = errors.New("router info does not declare a family")
+var ERR_FAMILY_KEY_INVALID = errors.New("invalid family key")
+var ERR_FAMILY_SIG_INVALID = errors.New("invalid family signature encoding")
+var ERR_FAMILY_SIGNING_TYPE_UNSUPPORTED = errors.New("unsupported family signing key type")
+
+//
+// Return the family name this RouterInfo declares, or an empty string if it
+// is not a member of a family.
+//
+func (router_info RouterInfo) Family() (family string) {
+	values, _ := router_info.Options().Values()
+	if value := values.Get(FAMILY_OPTION_NAME); value != nil {
+		family, _ = value.Data()
+	}
+	return
+}
+
+//
+// Return the SigningPublicKey published in the family.key option, selecting
+// the signing scheme from the key type prefix.
+//
+func (router_info RouterInfo) FamilyKey() (signing_public_key crypto.SigningPublicKey, err error) {
+	values, _ := router_info.Options().Values()
+	value := values.Get(FAMILY_OPTION_KEY)
+	if value == nil {
+		err = ERR_FAMILY_NOT_PRESENT
+		return
+	}
+	key_str, _ := value.Data()
+	parts := strings.SplitN(key_str, ";", 2)
+	if len(parts) != 2 {
+		err = ERR_FAMILY_KEY_INVALID
+		return
+	}
+	key_type, err := strconv.Atoi(parts[0])
+	if err != nil {
+		err = ERR_FAMILY_KEY_INVALID
+		return
+	}
+	key_data, err := base64.DecodeFromString(parts[1])
+	if err != nil {
+		err = ERR_FAMILY_KEY_INVALID
+		return
+	}
+	signing_public_key, err = familySigningPublicKey(key_type, key_data)
+	return
+}
+
+//
+// Verify the family.sig option of this RouterInfo against its family.key,
+// returning nil if the router is a verified member of the family it declares.
+//
+func (router_info RouterInfo) VerifyFamily() (err error) {
+	family := router_info.Family()
+	if family == "" {
+		err = ERR_FAMILY_NOT_PRESENT
+		return
+	}
+	signing_public_key, err := router_info.FamilyKey()
+	if err != nil {
+		return
+	}
+	values, _ := router_info.Options().Values()
+	value := values.Get(FAMILY_OPTION_SIG)
+	if value == nil {
+		err = ERR_FAMILY_NOT_PRESENT
+		return
+	}
+	sig_str, _ := value.Data()
+	sig, err := base64.DecodeFromString(sig_str)
+	if err != nil {
+		err = ERR_FAMILY_SIG_INVALID
+		return
+	}
+	ident_hash, err := router_info.IdentHash()
+	if err != nil {
+		return
+	}
+	verifier, err := signing_public_key.NewVerifier()
+	if err != nil {
+		return
+	}
+	err = verifier.Verify(append([]byte(family), ident_hash[:]...), sig)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"at":     "(RouterInfo) VerifyFamily",
+			"family": family,
+			"reason": err.Error(),
+		}).Warn("family verification failed")
+	}
+	return
+}
+
+//
+// Build a SigningPublicKey of the given Key Certificate signing type from the
+// raw key data in a family.key option.
+//
+func familySigningPublicKey(key_type int, data []byte) (signing_public_key crypto.SigningPublicKey, err error) {
+	sizes := map[int]int{
+		KEYCERT_SIGN_P256:    KEYCERT_SIGN_P256_SIZE,
+		KEYCERT_SIGN_P384:    KEYCERT_SIGN_P384_SIZE,
+		KEYCERT_SIGN_P521:    KEYCERT_SIGN_P521_SIZE,
+		KEYCERT_SIGN_ED25519: KEYCERT_SIGN_ED25519_SIZE,
+	}
+	size, ok := sizes[key_type]
+	if !ok {
+		log.WithFields(log.Fields{
+			"at":       "familySigningPublicKey",
+			"key_type": key_type,
+		}).Warn("unsupported family signing key type")
+		err = ERR_FAMILY_SIGNING_TYPE_UNSUPPORTED
+		return
+	}
+	if len(data) != size {
+		err = ERR_FAMILY_KEY_INVALID
+		return
+	}
+	switch key_type {
+	case KEYCERT_SIGN_P256:
+		var ec_key crypto.ECP256PublicKey
+		copy(ec_key[:], data)
+		signing_public_key = ec_key
+	case KEYCERT_SIGN_P384:
+		var ec_key crypto.ECP384PublicKey
+		copy(ec_key[:], data)
+		signing_public_key = ec_key
+	case KEYCERT_SIGN_P521:
+		var ec_key crypto.ECP521PublicKey
+		copy(ec_key[:], data)
+		signing_public_key = ec_key
+	case KEYCERT_SIGN_ED25519:
+		signing_public_key = crypto.Ed25519PublicKey(data)
+	}
+	return
+}
@@ -0,0 +1,111 @@
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"github.com/go-i2p/go-i2p/lib/common/base64"
+	"github.com/go-i2p/go-i2p/lib/crypto"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func familySignedData(family string) []byte {
+	ident_hash := HashData(buildRouterIdentity())
+	return append([]byte(family), ident_hash[:]...)
+}
+
+func paddedBytes(n *big.Int, size int) []byte {
+	data := make([]byte, size)
+	n_bytes := n.Bytes()
+	copy(data[size-len(n_bytes):], n_bytes)
+	return data
+}
+
+func buildEd25519FamilyRouterInfo(family string) RouterInfo {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := crypto.Ed25519PrivateKey(priv).NewSigner()
+	sig, _ := signer.Sign(familySignedData(family))
+	return buildRouterInfo(buildRouterIdentity(), nil, map[string]string{
+		FAMILY_OPTION_NAME: family,
+		FAMILY_OPTION_KEY:  fmt.Sprintf("%d;%s", KEYCERT_SIGN_ED25519, base64.EncodeToString(pub)),
+		FAMILY_OPTION_SIG:  base64.EncodeToString(sig),
+	})
+}
+
+func buildECDSAFamilyRouterInfo(family string) RouterInfo {
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	h := sha256.Sum256(familySignedData(family))
+	r, s, _ := ecdsa.Sign(rand.Reader, priv, h[:])
+	pub := append(paddedBytes(priv.X, 32), paddedBytes(priv.Y, 32)...)
+	sig := append(paddedBytes(r, 32), paddedBytes(s, 32)...)
+	return buildRouterInfo(buildRouterIdentity(), nil, map[string]string{
+		FAMILY_OPTION_NAME: family,
+		FAMILY_OPTION_KEY:  fmt.Sprintf("%d;%s", KEYCERT_SIGN_P256, base64.EncodeToString(pub)),
+		FAMILY_OPTION_SIG:  base64.EncodeToString(sig),
+	})
+}
+
+func TestFamilyReturnsDeclaredName(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildEd25519FamilyRouterInfo("i2p-dev")
+	assert.Equal("i2p-dev", router_info.Family())
+}
+
+func TestVerifyFamilyWithEd25519Family(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildEd25519FamilyRouterInfo("i2p-dev")
+	key, err := router_info.FamilyKey()
+	assert.Nil(err)
+	assert.IsType(crypto.Ed25519PublicKey{}, key)
+	assert.Nil(router_info.VerifyFamily())
+}
+
+func TestVerifyFamilyWithECDSAFamily(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildECDSAFamilyRouterInfo("i2p-dev")
+	key, err := router_info.FamilyKey()
+	assert.Nil(err)
+	assert.IsType(crypto.ECP256PublicKey{}, key)
+	assert.Nil(router_info.VerifyFamily())
+}
+
+func TestVerifyFamilyFailsForOtherFamilyName(t *testing.T) {
+	assert := assert.New(t)
+
+	signed := buildECDSAFamilyRouterInfo("i2p-dev")
+	values, _ := signed.Options().Values()
+	key, _ := values.Get(FAMILY_OPTION_KEY).Data()
+	sig, _ := values.Get(FAMILY_OPTION_SIG).Data()
+	router_info := buildRouterInfo(buildRouterIdentity(), nil, map[string]string{
+		FAMILY_OPTION_NAME: "not-i2p-dev",
+		FAMILY_OPTION_KEY:  key,
+		FAMILY_OPTION_SIG:  sig,
+	})
+	assert.Equal(crypto.ErrInvalidSignature, router_info.VerifyFamily())
+}
+
+func TestVerifyFamilyWithoutFamily(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildRouterInfo(buildRouterIdentity(), nil, map[string]string{"caps": "L"})
+	assert.Equal(ERR_FAMILY_NOT_PRESENT, router_info.VerifyFamily())
+}
+
+func TestFamilyKeyWithUnsupportedType(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildRouterInfo(buildRouterIdentity(), nil, map[string]string{
+		FAMILY_OPTION_NAME: "i2p-dev",
+		FAMILY_OPTION_KEY:  fmt.Sprintf("%d;%s", KEYCERT_SIGN_RSA2048, base64.EncodeToString(make([]byte, 16))),
+	})
+	_, err := router_info.FamilyKey()
+	assert.Equal(ERR_FAMILY_SIGNING_TYPE_UNSUPPORTED, err)
+}
@@ -1,7 +1,7 @@
 package common
 
 import (
-	"crypto/sha256"
+	"github.com/go-i2p/go-i2p/lib/crypto"
 	"io"
 )
 
@@ -10,14 +10,14 @@ type Hash [32]byte
 
 // calculate sha256 of a byte slice
 func HashData(data []byte) (h Hash) {
-	h = sha256.Sum256(data)
+	h = crypto.SHA256(data)
 	return
 }
 
 // calulate sha256 of all data being read from an io.Reader
 // return error if one occurs while reading from reader
 func HashReader(r io.Reader) (h Hash, err error) {
-	sha := sha256.New()
+	sha := crypto.NewSHA256()
 	_, err = io.Copy(sha, r)
 	if err == nil {
 		d := sha.Sum(nil)
@@ -0,0 +1,33 @@
+package common
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHashDataMatchesSHA256(t *testing.T) {
+	assert := assert.New(t)
+
+	ident := buildRouterIdentity()
+	assert.Equal(Hash(sha256.Sum256(ident)), HashData(ident))
+}
+
+func TestHashReaderMatchesHashData(t *testing.T) {
+	assert := assert.New(t)
+
+	ident := buildRouterIdentity()
+	h, err := HashReader(bytes.NewReader(ident))
+	assert.Nil(err)
+	assert.Equal(HashData(ident), h)
+}
+
+func TestIdentHashUsesHashData(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildFullRouterInfo()
+	h, err := router_info.IdentHash()
+	assert.Nil(err)
+	assert.Equal(Hash(sha256.Sum256(buildRouterIdentity())), h)
+}
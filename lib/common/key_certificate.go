@@ -48,6 +48,10 @@ const (
 // Key Certificate Public Key Types
 const (
 	KEYCERT_CRYPTO_ELG = iota
+	KEYCERT_CRYPTO_P256
+	KEYCERT_CRYPTO_P384
+	KEYCERT_CRYPTO_P521
+	KEYCERT_CRYPTO_X25519
 )
 
 // SigningPublicKey sizes for Signing Key Types
@@ -76,6 +80,18 @@ const (
 
 type KeyCertificate []byte
 
+//
+// Build a Key Certificate for a signing key type and a crypto key type without any
+// excess key data.
+//
+func NewKeyCertificate(signing_key_type, crypto_key_type int) KeyCertificate {
+	return KeyCertificate{
+		CERT_KEY, 0x00, CERT_KEY_MIN_LENGTH,
+		byte(signing_key_type >> 8), byte(signing_key_type),
+		byte(crypto_key_type >> 8), byte(crypto_key_type),
+	}
+}
+
 //
 // The data contained in the Key Certificate.
 //
@@ -155,11 +171,17 @@ func (key_certificate KeyCertificate) ConstructPublicKey(data []byte) (public_ke
 		err = errors.New("error constructing public key: not enough data")
 		return
 	}
+	size := PublicKeySize(key_type)
 	switch key_type {
 	case KEYCERT_CRYPTO_ELG:
 		var elg_key crypto.ElgPublicKey
-		copy(elg_key[:], data[KEYCERT_PUBKEY_SIZE-KEYCERT_CRYPTO_ELG_SIZE:KEYCERT_PUBKEY_SIZE])
+		copy(elg_key[:], data[KEYCERT_PUBKEY_SIZE-size:KEYCERT_PUBKEY_SIZE])
 		public_key = elg_key
+	case KEYCERT_CRYPTO_X25519:
+		// crypto public keys shorter than the field are aligned at its start
+		var x25519_key crypto.X25519PublicKey
+		copy(x25519_key[:], data[:size])
+		public_key = x25519_key
 	}
 	return
 }
@@ -169,7 +191,7 @@ func (key_certificate KeyCertificate) ConstructPublicKey(data []byte) (public_ke
 // it along with any errors encountered constructing the SigningPublicKey.
 //
 func (key_certificate KeyCertificate) ConstructSigningPublicKey(data []byte) (signing_public_key crypto.SigningPublicKey, err error) {
-	signing_key_type, err := key_certificate.PublicKeyType()
+	signing_key_type, err := key_certificate.SigningPublicKeyType()
 	if err != nil {
 		return
 	}
@@ -184,24 +206,38 @@ func (key_certificate KeyCertificate) ConstructSigningPublicKey(data []byte) (si
 		err = errors.New("error constructing signing public key: not enough data")
 		return
 	}
+	// signing public keys shorter than the field are aligned at its end
+	size := SigningPublicKeySize(signing_key_type)
 	switch signing_key_type {
 	case KEYCERT_SIGN_DSA_SHA1:
 		var dsa_key crypto.DSAPublicKey
-		copy(dsa_key[:], data[KEYCERT_SPK_SIZE-KEYCERT_SIGN_DSA_SHA1_SIZE:KEYCERT_SPK_SIZE])
+		copy(dsa_key[:], data[KEYCERT_SPK_SIZE-size:KEYCERT_SPK_SIZE])
 		signing_public_key = dsa_key
 	case KEYCERT_SIGN_P256:
 		var ec_key crypto.ECP256PublicKey
-		copy(ec_key[:], data[KEYCERT_SPK_SIZE-KEYCERT_SIGN_P256_SIZE:KEYCERT_SPK_SIZE])
+		copy(ec_key[:], data[KEYCERT_SPK_SIZE-size:KEYCERT_SPK_SIZE])
 		signing_public_key = ec_key
 	case KEYCERT_SIGN_P384:
 		var ec_key crypto.ECP384PublicKey
-		copy(ec_key[:], data[KEYCERT_SPK_SIZE-KEYCERT_SIGN_P384_SIZE:KEYCERT_SPK_SIZE])
+		copy(ec_key[:], data[KEYCERT_SPK_SIZE-size:KEYCERT_SPK_SIZE])
 		signing_public_key = ec_key
 	case KEYCERT_SIGN_P521:
+		// the excess key data follows the certificate header and the two key types
 		var ec_key crypto.ECP521PublicKey
-		extra := KEYCERT_SIGN_P521_SIZE - KEYCERT_SPK_SIZE
-		copy(ec_key[:], data)
-		copy(ec_key[KEYCERT_SPK_SIZE:], key_certificate[4:4+extra])
+		extra := size - KEYCERT_SPK_SIZE
+		extra_start := CERT_MIN_SIZE + CERT_KEY_MIN_LENGTH
+		if len(key_certificate) < extra_start+extra {
+			log.WithFields(log.Fields{
+				"at":           "(KeyCertificate) ConstructSigningPublicKey",
+				"cert_len":     len(key_certificate),
+				"required_len": extra_start + extra,
+				"reason":       "not enough excess signing key data",
+			}).Error("error constructing signing public key")
+			err = errors.New("error constructing signing public key: not enough excess key data")
+			return
+		}
+		copy(ec_key[:], data[:KEYCERT_SPK_SIZE])
+		copy(ec_key[KEYCERT_SPK_SIZE:], key_certificate[extra_start:extra_start+extra])
 		signing_public_key = ec_key
 	case KEYCERT_SIGN_RSA2048:
 		//var rsa_key crypto.RSA2048PublicKey
@@ -212,6 +248,9 @@ func (key_certificate KeyCertificate) ConstructSigningPublicKey(data []byte) (si
 	case KEYCERT_SIGN_RSA3072:
 	case KEYCERT_SIGN_RSA4096:
 	case KEYCERT_SIGN_ED25519:
+		ed_key := make(crypto.Ed25519PublicKey, size)
+		copy(ed_key, data[KEYCERT_SPK_SIZE-size:KEYCERT_SPK_SIZE])
+		signing_public_key = ed_key
 	case KEYCERT_SIGN_ED25519PH:
 	}
 	return
@@ -222,17 +261,6 @@ func (key_certificate KeyCertificate) ConstructSigningPublicKey(data []byte) (si
 // SigningPublicKey type.
 //
 func (key_certificate KeyCertificate) SignatureSize() (size int) {
-	sizes := map[int]int{
-		KEYCERT_SIGN_DSA_SHA1:  40,
-		KEYCERT_SIGN_P256:      64,
-		KEYCERT_SIGN_P384:      96,
-		KEYCERT_SIGN_P521:      132,
-		KEYCERT_SIGN_RSA2048:   256,
-		KEYCERT_SIGN_RSA3072:   384,
-		KEYCERT_SIGN_RSA4096:   512,
-		KEYCERT_SIGN_ED25519:   64,
-		KEYCERT_SIGN_ED25519PH: 64,
-	}
 	key_type, err := key_certificate.SigningPublicKeyType()
 	if err != nil {
 		log.WithFields(log.Fields{
@@ -242,5 +270,5 @@ func (key_certificate KeyCertificate) SignatureSize() (size int) {
 		}).Error("error getting signature size")
 		return 0
 	}
-	return sizes[int(key_type)]
+	return SignatureSizeForType(key_type)
 }
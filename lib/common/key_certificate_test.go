@@ -1,6 +1,7 @@
 package common
 
 import (
+	"github.com/go-i2p/go-i2p/lib/crypto"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -125,3 +126,35 @@ func TestConstructSigningPublicKeyWithP521(t *testing.T) {
 	assert.Nil(err, "ConstructSigningPublicKey() with P521 returned err on valid data")
 	assert.Equal(spk.Len(), KEYCERT_SIGN_P521_SIZE, "ConstructSigningPublicKey() with P521 returned incorrect SigningPublicKey length")
 }
+
+func TestConstructSigningPublicKeyWithP521UsesExcessKeyData(t *testing.T) {
+	assert := assert.New(t)
+
+	key_cert := KeyCertificate([]byte{0x05, 0x00, 0x08, 0x00, 0x03, 0x00, 0x03, 0xa1, 0xa2, 0xa3, 0xa4})
+	data := make([]byte, 128)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	spk, err := key_cert.ConstructSigningPublicKey(data)
+	if assert.Nil(err) {
+		ec_key := spk.(crypto.ECP521PublicKey)
+		assert.Equal(data, ec_key[:128], "the SigningPublicKey field holds the first 128 bytes")
+		assert.Equal([]byte{0xa1, 0xa2, 0xa3, 0xa4}, ec_key[128:], "the excess 4 bytes follow the key types")
+	}
+
+	_, err = KeyCertificate([]byte{0x05, 0x00, 0x06, 0x00, 0x03, 0x00, 0x03, 0xa1, 0xa2}).ConstructSigningPublicKey(data)
+	assert.NotNil(err, "excess key data shorter than 4 bytes")
+}
+
+func TestNewKeyCertificateHoldsKeyTypes(t *testing.T) {
+	assert := assert.New(t)
+
+	key_cert := NewKeyCertificate(KEYCERT_SIGN_ED25519, KEYCERT_CRYPTO_X25519)
+	signing_pubkey_type, err := key_cert.SigningPublicKeyType()
+	assert.Nil(err)
+	assert.Equal(KEYCERT_SIGN_ED25519, signing_pubkey_type)
+	pubkey_type, err := key_cert.PublicKeyType()
+	assert.Nil(err)
+	assert.Equal(KEYCERT_CRYPTO_X25519, pubkey_type)
+	assert.Equal(KeyCertificate{0x05, 0x00, 0x04, 0x00, 0x07, 0x00, 0x04}, key_cert)
+}
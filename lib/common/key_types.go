@@ -0,0 +1,145 @@
+package common
+
+/*
+I2P Key Certificate key types
+https://geti2p.net/spec/common-structures#key-certificates
+Accurate for version 0.9.24
+
+Signing key types:
+
+type                  | code | public key | signature
+----------------------+------+------------+----------
+DSA_SHA1              |    0 |        128 |        40
+ECDSA_SHA256_P256     |    1 |         64 |        64
+ECDSA_SHA384_P384     |    2 |         96 |        96
+ECDSA_SHA512_P521     |    3 |        132 |       132
+RSA_SHA256_2048       |    4 |        256 |       256
+RSA_SHA384_3072       |    5 |        384 |       384
+RSA_SHA512_4096       |    6 |        512 |       512
+EdDSA_SHA512_Ed25519  |    7 |         32 |        64
+EdDSA_SHA512_Ed25519ph|    8 |         32 |        64
+
+Crypto public key types:
+
+type    | code | public key
+--------+------+-----------
+ElGamal |    0 |        256
+P256    |    1 |         64
+P384    |    2 |         96
+P521    |    3 |        132
+X25519  |    4 |         32
+*/
+
+// PublicKey sizes for the Public Key Types not used in Destinations
+const (
+	KEYCERT_CRYPTO_P256_SIZE   = 64
+	KEYCERT_CRYPTO_P384_SIZE   = 96
+	KEYCERT_CRYPTO_P521_SIZE   = 132
+	KEYCERT_CRYPTO_X25519_SIZE = 32
+)
+
+type signingKeyType struct {
+	name           string
+	public_key_len int
+	signature_len  int
+}
+
+type cryptoKeyType struct {
+	name           string
+	public_key_len int
+}
+
+var signingKeyTypes = map[int]signingKeyType{
+	KEYCERT_SIGN_DSA_SHA1:  {"DSA_SHA1", KEYCERT_SIGN_DSA_SHA1_SIZE, 40},
+	KEYCERT_SIGN_P256:      {"ECDSA_SHA256_P256", KEYCERT_SIGN_P256_SIZE, 64},
+	KEYCERT_SIGN_P384:      {"ECDSA_SHA384_P384", KEYCERT_SIGN_P384_SIZE, 96},
+	KEYCERT_SIGN_P521:      {"ECDSA_SHA512_P521", KEYCERT_SIGN_P521_SIZE, 132},
+	KEYCERT_SIGN_RSA2048:   {"RSA_SHA256_2048", KEYCERT_SIGN_RSA2048_SIZE, 256},
+	KEYCERT_SIGN_RSA3072:   {"RSA_SHA384_3072", KEYCERT_SIGN_RSA3072_SIZE, 384},
+	KEYCERT_SIGN_RSA4096:   {"RSA_SHA512_4096", KEYCERT_SIGN_RSA4096_SIZE, 512},
+	KEYCERT_SIGN_ED25519:   {"EdDSA_SHA512_Ed25519", KEYCERT_SIGN_ED25519_SIZE, 64},
+	KEYCERT_SIGN_ED25519PH: {"EdDSA_SHA512_Ed25519ph", KEYCERT_SIGN_ED25519PH_SIZE, 64},
+}
+
+var cryptoKeyTypes = map[int]cryptoKeyType{
+	KEYCERT_CRYPTO_ELG:    {"ElGamal", KEYCERT_CRYPTO_ELG_SIZE},
+	KEYCERT_CRYPTO_P256:   {"P256", KEYCERT_CRYPTO_P256_SIZE},
+	KEYCERT_CRYPTO_P384:   {"P384", KEYCERT_CRYPTO_P384_SIZE},
+	KEYCERT_CRYPTO_P521:   {"P521", KEYCERT_CRYPTO_P521_SIZE},
+	KEYCERT_CRYPTO_X25519: {"X25519", KEYCERT_CRYPTO_X25519_SIZE},
+}
+
+var certificateTypeNames = map[int]string{
+	CERT_NULL:     "NULL",
+	CERT_HASHCASH: "HASHCASH",
+	CERT_HIDDEN:   "HIDDEN",
+	CERT_SIGNED:   "SIGNED",
+	CERT_MULTIPLE: "MULTIPLE",
+	CERT_KEY:      "KEY",
+}
+
+//
+// Return the spec name of a signing key type, or an empty string if it is unknown.
+//
+func SigningKeyTypeName(signing_key_type int) string {
+	return signingKeyTypes[signing_key_type].name
+}
+
+//
+// Return the signing key type with the given spec name and whether it was found.
+//
+func SigningKeyTypeByName(name string) (signing_key_type int, ok bool) {
+	for key_type, info := range signingKeyTypes {
+		if info.name == name {
+			return key_type, true
+		}
+	}
+	return
+}
+
+//
+// Return the length of a SigningPublicKey of a signing key type, or 0 if it is unknown.
+//
+func SigningPublicKeySize(signing_key_type int) int {
+	return signingKeyTypes[signing_key_type].public_key_len
+}
+
+//
+// Return the length of a Signature made with a signing key type, or 0 if it is unknown.
+//
+func SignatureSizeForType(signing_key_type int) int {
+	return signingKeyTypes[signing_key_type].signature_len
+}
+
+//
+// Return the spec name of a crypto public key type, or an empty string if it is unknown.
+//
+func CryptoKeyTypeName(crypto_key_type int) string {
+	return cryptoKeyTypes[crypto_key_type].name
+}
+
+//
+// Return the crypto public key type with the given spec name and whether it was found.
+//
+func CryptoKeyTypeByName(name string) (crypto_key_type int, ok bool) {
+	for key_type, info := range cryptoKeyTypes {
+		if info.name == name {
+			return key_type, true
+		}
+	}
+	return
+}
+
+//
+// Return the length of a PublicKey of a crypto public key type, or 0 if it is unknown.
+//
+func PublicKeySize(crypto_key_type int) int {
+	return cryptoKeyTypes[crypto_key_type].public_key_len
+}
+
+//
+// Return the spec name of a Certificate type, or an empty string if it is unknown.
+//
+func CertificateTypeName(cert_type int) string {
+	return certificateTypeNames[cert_type]
+}
@@ -0,0 +1,90 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSigningKeyTypeLengthsMatchSpec(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := []struct {
+		key_type      int
+		name          string
+		public_key    int
+		signature_len int
+	}{
+		{0, "DSA_SHA1", 128, 40},
+		{1, "ECDSA_SHA256_P256", 64, 64},
+		{2, "ECDSA_SHA384_P384", 96, 96},
+		{3, "ECDSA_SHA512_P521", 132, 132},
+		{4, "RSA_SHA256_2048", 256, 256},
+		{5, "RSA_SHA384_3072", 384, 384},
+		{6, "RSA_SHA512_4096", 512, 512},
+		{7, "EdDSA_SHA512_Ed25519", 32, 64},
+		{8, "EdDSA_SHA512_Ed25519ph", 32, 64},
+	}
+	for _, row := range spec {
+		assert.Equal(row.name, SigningKeyTypeName(row.key_type))
+		assert.Equal(row.public_key, SigningPublicKeySize(row.key_type), row.name)
+		assert.Equal(row.signature_len, SignatureSizeForType(row.key_type), row.name)
+		key_type, ok := SigningKeyTypeByName(row.name)
+		assert.True(ok, row.name)
+		assert.Equal(row.key_type, key_type)
+	}
+	assert.Equal("", SigningKeyTypeName(99))
+	assert.Equal(0, SignatureSizeForType(99))
+	_, ok := SigningKeyTypeByName("RedDSA")
+	assert.False(ok)
+}
+
+func TestCryptoKeyTypeLengthsMatchSpec(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := []struct {
+		key_type   int
+		name       string
+		public_key int
+	}{
+		{0, "ElGamal", 256},
+		{1, "P256", 64},
+		{2, "P384", 96},
+		{3, "P521", 132},
+		{4, "X25519", 32},
+	}
+	for _, row := range spec {
+		assert.Equal(row.name, CryptoKeyTypeName(row.key_type))
+		assert.Equal(row.public_key, PublicKeySize(row.key_type), row.name)
+		key_type, ok := CryptoKeyTypeByName(row.name)
+		assert.True(ok, row.name)
+		assert.Equal(row.key_type, key_type)
+	}
+	assert.Equal(0, PublicKeySize(99))
+}
+
+func TestCertificateTypeNames(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("NULL", CertificateTypeName(CERT_NULL))
+	assert.Equal("KEY", CertificateTypeName(CERT_KEY))
+	assert.Equal("", CertificateTypeName(6))
+}
+
+func TestConstructedKeyLengthsMatchTables(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, signing_key_type := range []int{KEYCERT_SIGN_DSA_SHA1, KEYCERT_SIGN_P256, KEYCERT_SIGN_P384, KEYCERT_SIGN_P521, KEYCERT_SIGN_ED25519} {
+		key_cert := KeyCertificate([]byte{0x05, 0x00, 0x08, 0x00, byte(signing_key_type), 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})
+		spk, err := key_cert.ConstructSigningPublicKey(make([]byte, KEYCERT_SPK_SIZE))
+		if assert.Nil(err, SigningKeyTypeName(signing_key_type)) && assert.NotNil(spk, SigningKeyTypeName(signing_key_type)) {
+			assert.Equal(SigningPublicKeySize(signing_key_type), spk.Len(), SigningKeyTypeName(signing_key_type))
+		}
+	}
+	for _, crypto_key_type := range []int{KEYCERT_CRYPTO_ELG, KEYCERT_CRYPTO_X25519} {
+		key_cert := KeyCertificate([]byte{0x05, 0x00, 0x04, 0x00, 0x00, 0x00, byte(crypto_key_type)})
+		pk, err := key_cert.ConstructPublicKey(make([]byte, KEYCERT_PUBKEY_SIZE))
+		if assert.Nil(err, CryptoKeyTypeName(crypto_key_type)) && assert.NotNil(pk, CryptoKeyTypeName(crypto_key_type)) {
+			assert.Equal(PublicKeySize(crypto_key_type), pk.Len(), CryptoKeyTypeName(crypto_key_type))
+		}
+	}
+}
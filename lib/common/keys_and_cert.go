@@ -59,6 +59,9 @@ const (
 	KEYS_AND_CERT_DATA_SIZE   = 384
 )
 
+var ERR_ENCRYPTION_KEY_UNSUPPORTED = errors.New("unsupported crypto public key type")
+var ERR_KEYS_AND_CERT_KEY_TOO_LARGE = errors.New("key does not fit in its KeysAndCert field")
+
 type KeysAndCert []byte
 
 //
@@ -66,6 +69,12 @@ type KeysAndCert []byte
 // determine correct lengths.
 //
 func (keys_and_cert KeysAndCert) PublicKey() (key crypto.PublicKey, err error) {
+	if keys_and_cert.hasNullCertificate() {
+		var elg_key crypto.ElgPublicKey
+		copy(elg_key[:], keys_and_cert[:KEYS_AND_CERT_PUBKEY_SIZE])
+		key = elg_key
+		return
+	}
 	cert, err := keys_and_cert.Certificate()
 	if err != nil {
 		return
@@ -78,7 +87,7 @@ func (keys_and_cert KeysAndCert) PublicKey() (key crypto.PublicKey, err error) {
 		// No Certificate is present, return the KEYS_AND_CERT_PUBKEY_SIZE byte
 		// PublicKey space as ElgPublicKey.
 		var elg_key crypto.ElgPublicKey
-		copy(keys_and_cert[:KEYS_AND_CERT_PUBKEY_SIZE], elg_key[:])
+		copy(elg_key[:], keys_and_cert[:KEYS_AND_CERT_PUBKEY_SIZE])
 		key = elg_key
 	} else {
 		// A Certificate is present in this KeysAndCert
@@ -95,23 +104,42 @@ func (keys_and_cert KeysAndCert) PublicKey() (key crypto.PublicKey, err error) {
 			// PublicKey space as ElgPublicKey.  No other Certificate
 			// types are currently in use.
 			var elg_key crypto.ElgPublicKey
-			copy(keys_and_cert[:KEYS_AND_CERT_PUBKEY_SIZE], elg_key[:])
+			copy(elg_key[:], keys_and_cert[:KEYS_AND_CERT_PUBKEY_SIZE])
 			key = elg_key
-			log.WithFields(log.Fields{
+			parseLog.Warn(log.Fields{
 				"at":        "(KeysAndCert) PublicKey",
 				"cert_type": cert_type,
-			}).Warn("unused certificate type observed")
+			}, "unused certificate type observed")
 		}
 
 	}
 	return
 }
 
+//
+// Return the key to encrypt to for this KeysAndCert, typed by the Key Certificate as an
+// ElgPublicKey or X25519PublicKey, or ERR_ENCRYPTION_KEY_UNSUPPORTED if the crypto key
+// type is one we cannot construct.
+//
+func (keys_and_cert KeysAndCert) EncryptionKey() (key crypto.PublicKey, err error) {
+	key, err = keys_and_cert.PublicKey()
+	if err == nil && key == nil {
+		err = ERR_ENCRYPTION_KEY_UNSUPPORTED
+	}
+	return
+}
+
 //
 // Return the SigningPublicKey for this KeysAndCert, reading from the Key Certificate if it is present to
 // determine correct lengths.
 //
 func (keys_and_cert KeysAndCert) SigningPublicKey() (signing_public_key crypto.SigningPublicKey, err error) {
+	if keys_and_cert.hasNullCertificate() {
+		var dsa_pk crypto.DSAPublicKey
+		copy(dsa_pk[:], keys_and_cert[KEYS_AND_CERT_PUBKEY_SIZE:KEYS_AND_CERT_PUBKEY_SIZE+KEYS_AND_CERT_SPK_SIZE])
+		signing_public_key = dsa_pk
+		return
+	}
 	cert, err := keys_and_cert.Certificate()
 	if err != nil {
 		return
@@ -149,6 +177,17 @@ func (keys_and_cert KeysAndCert) SigningPublicKey() (signing_public_key crypto.S
 	return
 }
 
+//
+// Return true if the KeysAndCert ends in a NULL Certificate, so the keys are the
+// default ElGamal and DSA_SHA1 keys and no Key Certificate needs to be parsed.
+//
+func (keys_and_cert KeysAndCert) hasNullCertificate() bool {
+	return len(keys_and_cert) >= KEYS_AND_CERT_MIN_SIZE &&
+		keys_and_cert[KEYS_AND_CERT_DATA_SIZE] == CERT_NULL &&
+		keys_and_cert[KEYS_AND_CERT_DATA_SIZE+1] == 0x00 &&
+		keys_and_cert[KEYS_AND_CERT_DATA_SIZE+2] == 0x00
+}
+
 //
 // Return the Certificate contained in the KeysAndCert and any errors encountered while parsing the
 // KeysAndCert or Certificate.
@@ -169,6 +208,23 @@ func (keys_and_cert KeysAndCert) Certificate() (cert Certificate, err error) {
 	return
 }
 
+//
+// Build a KeysAndCert from the bytes of its keys and its Certificate.  The PublicKey is
+// aligned at the start of its field and the SigningPublicKey at the end of its field, as
+// ConstructPublicKey and ConstructSigningPublicKey read them; the rest is left zero.
+//
+func NewKeysAndCert(public_key, signing_public_key []byte, cert Certificate) (keys_and_cert KeysAndCert, err error) {
+	if len(public_key) > KEYS_AND_CERT_PUBKEY_SIZE || len(signing_public_key) > KEYS_AND_CERT_SPK_SIZE {
+		err = ERR_KEYS_AND_CERT_KEY_TOO_LARGE
+		return
+	}
+	keys_and_cert = make(KeysAndCert, KEYS_AND_CERT_DATA_SIZE, KEYS_AND_CERT_DATA_SIZE+len(cert))
+	copy(keys_and_cert, public_key)
+	copy(keys_and_cert[KEYS_AND_CERT_DATA_SIZE-len(signing_public_key):], signing_public_key)
+	keys_and_cert = append(keys_and_cert, cert...)
+	return
+}
+
 //
 // Read a KeysAndCert from a slice of bytes, retuning it and the remaining data as well as any errors
 // encoutered parsing the KeysAndCert.
@@ -186,6 +242,10 @@ func ReadKeysAndCert(data []byte) (keys_and_cert KeysAndCert, remainder []byte,
 		return
 	}
 	keys_and_cert = KeysAndCert(data[:KEYS_AND_CERT_MIN_SIZE])
+	if keys_and_cert.hasNullCertificate() {
+		remainder = data[KEYS_AND_CERT_MIN_SIZE:]
+		return
+	}
 	cert, _ := keys_and_cert.Certificate()
 	cert_len, cert_len_err := cert.Length()
 	if cert_len == 0 {
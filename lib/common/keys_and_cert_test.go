@@ -1,6 +1,7 @@
 package common
 
 import (
+	"github.com/go-i2p/go-i2p/lib/crypto"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -164,7 +165,7 @@ func TestSigningPublicKeyWithKeyCertificate(t *testing.T) {
 
 	signing_pub_key, err := keys_and_cert.SigningPublicKey()
 	assert.Nil(err)
-	assert.Equal(len(signing_pub_key_data), signing_pub_key.Len())
+	assert.Equal(KEYCERT_SIGN_P256_SIZE, signing_pub_key.Len())
 }
 
 func TestReadKeysAndCertWithMissingData(t *testing.T) {
@@ -287,3 +288,113 @@ func TestReadKeysAndCertWithValidDataWithoutCertificateAndRemainder(t *testing.T
 	_, err = keys_and_cert.Certificate()
 	assert.Nil(err, "keys_and_cert.Certificate() returned error with valid data not containing certificate")
 }
+
+func buildKeysAndCertWithPublicKey(public_key []byte, cert_data []byte) KeysAndCert {
+	data := make([]byte, KEYS_AND_CERT_PUBKEY_SIZE)
+	copy(data, public_key)
+	data = append(data, make([]byte, KEYS_AND_CERT_SPK_SIZE)...)
+	return KeysAndCert(append(data, cert_data...))
+}
+
+func TestEncryptionKeyForElGamalDestination(t *testing.T) {
+	assert := assert.New(t)
+
+	public_key := make([]byte, KEYCERT_CRYPTO_ELG_SIZE)
+	for i := range public_key {
+		public_key[i] = byte(i)
+	}
+	destination := Destination(buildKeysAndCertWithPublicKey(public_key, []byte{0x00, 0x00, 0x00}))
+	key, err := destination.EncryptionKey()
+	assert.Nil(err)
+	if assert.IsType(crypto.ElgPublicKey{}, key) {
+		elg_key := key.(crypto.ElgPublicKey)
+		assert.Equal(public_key, elg_key[:])
+	}
+}
+
+func TestEncryptionKeyForX25519RouterIdentity(t *testing.T) {
+	assert := assert.New(t)
+
+	public_key := make([]byte, KEYCERT_CRYPTO_X25519_SIZE)
+	for i := range public_key {
+		public_key[i] = byte(0xa0 + i)
+	}
+	cert_data := []byte{0x05, 0x00, 0x04, 0x00, KEYCERT_SIGN_ED25519, 0x00, KEYCERT_CRYPTO_X25519}
+	router_identity := RouterIdentity(buildKeysAndCertWithPublicKey(public_key, cert_data))
+	key, err := router_identity.EncryptionKey()
+	assert.Nil(err)
+	if assert.IsType(crypto.X25519PublicKey{}, key) {
+		x25519_key := key.(crypto.X25519PublicKey)
+		assert.Equal(public_key, x25519_key[:])
+	}
+}
+
+func TestEncryptionKeyWithUnsupportedType(t *testing.T) {
+	assert := assert.New(t)
+
+	cert_data := []byte{0x05, 0x00, 0x04, 0x00, KEYCERT_SIGN_ED25519, 0x00, KEYCERT_CRYPTO_P256}
+	_, err := buildKeysAndCertWithPublicKey(nil, cert_data).EncryptionKey()
+	assert.Equal(ERR_ENCRYPTION_KEY_UNSUPPORTED, err)
+}
+
+func TestNullCertificateIdentityUsesDefaultKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	data := make([]byte, KEYS_AND_CERT_DATA_SIZE)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	data = append(data, []byte{0x00, 0x00, 0x00, 0xaa, 0xbb}...)
+	keys_and_cert, remainder, err := ReadKeysAndCert(data)
+	assert.Nil(err)
+	assert.Equal([]byte{0xaa, 0xbb}, remainder)
+	assert.Equal(KEYS_AND_CERT_MIN_SIZE, len(keys_and_cert))
+
+	pub_key, err := keys_and_cert.PublicKey()
+	assert.Nil(err)
+	if assert.IsType(crypto.ElgPublicKey{}, pub_key) {
+		elg_key := pub_key.(crypto.ElgPublicKey)
+		assert.Equal(data[:KEYS_AND_CERT_PUBKEY_SIZE], elg_key[:])
+	}
+	signing_key, err := keys_and_cert.SigningPublicKey()
+	assert.Nil(err)
+	if assert.IsType(crypto.DSAPublicKey{}, signing_key) {
+		dsa_key := signing_key.(crypto.DSAPublicKey)
+		assert.Equal(data[KEYS_AND_CERT_PUBKEY_SIZE:KEYS_AND_CERT_DATA_SIZE], dsa_key[:])
+	}
+}
+
+func TestNewKeysAndCertAlignsKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	public_key := make([]byte, 256)
+	public_key[0] = 0x01
+	signing_public_key := make([]byte, 32)
+	signing_public_key[31] = 0x02
+	cert := Certificate(NewKeyCertificate(KEYCERT_SIGN_ED25519, KEYCERT_CRYPTO_ELG))
+	keys_and_cert, err := NewKeysAndCert(public_key, signing_public_key, cert)
+	assert.Nil(err)
+	assert.Equal(KEYS_AND_CERT_DATA_SIZE+len(cert), len(keys_and_cert))
+
+	pub_key, err := keys_and_cert.PublicKey()
+	if assert.Nil(err) {
+		elg_key := pub_key.(crypto.ElgPublicKey)
+		assert.Equal(public_key, elg_key[:])
+	}
+	sign_pub_key, err := keys_and_cert.SigningPublicKey()
+	if assert.Nil(err) {
+		assert.Equal(crypto.Ed25519PublicKey(signing_public_key), sign_pub_key)
+	}
+	read_cert, err := keys_and_cert.Certificate()
+	assert.Nil(err)
+	assert.Equal(cert, read_cert)
+}
+
+func TestNewKeysAndCertRejectsOversizedKey(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewKeysAndCert(make([]byte, 257), nil, Certificate{CERT_NULL, 0x00, 0x00})
+	assert.Equal(ERR_KEYS_AND_CERT_KEY_TOO_LARGE, err)
+	_, err = NewKeysAndCert(nil, make([]byte, 129), Certificate{CERT_NULL, 0x00, 0x00})
+	assert.Equal(ERR_KEYS_AND_CERT_KEY_TOO_LARGE, err)
+}
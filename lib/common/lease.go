@@ -29,6 +29,10 @@ end_date :: Date
             length -> 8 bytes
 */
 
+import (
+	"errors"
+)
+
 // Sizes or various components of a Lease
 const (
 	LEASE_SIZE           = 44
@@ -36,6 +40,8 @@ const (
 	LEASE_TUNNEL_ID_SIZE = 4
 )
 
+var ERR_LEASE_NOT_ENOUGH_DATA = errors.New("error parsing lease: not enough data")
+
 type Lease [LEASE_SIZE]byte
 
 //
@@ -62,3 +68,24 @@ func (lease Lease) Date() (date Date) {
 	copy(date[:], lease[LEASE_HASH_SIZE+LEASE_TUNNEL_ID_SIZE:])
 	return
 }
+
+//
+// Return the Lease in its wire form.
+//
+func (lease Lease) Bytes() []byte {
+	return lease[:]
+}
+
+//
+// Read a Lease from the start of data, returning it and the remaining bytes or
+// ERR_LEASE_NOT_ENOUGH_DATA if data is shorter than a Lease.
+//
+func ReadLease(data []byte) (lease Lease, remainder []byte, err error) {
+	if len(data) < LEASE_SIZE {
+		err = ERR_LEASE_NOT_ENOUGH_DATA
+		return
+	}
+	copy(lease[:], data[:LEASE_SIZE])
+	remainder = data[LEASE_SIZE:]
+	return
+}
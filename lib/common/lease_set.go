@@ -127,7 +127,8 @@ func (lease_set LeaseSet) PublicKey() (public_key crypto.ElgPublicKey, err error
 
 //
 // Return the SigningPublicKey, as specified in the LeaseSet's Destination's Key Certificate if
-// present, or a legacy DSA key.
+// present, or a legacy DSA key.  This is the unused revocation key of the LeaseSet, not the
+// key its Signature is checked with; use the Destination's SigningPublicKey for that.
 //
 func (lease_set LeaseSet) SigningKey() (signing_public_key crypto.SigningPublicKey, err error) {
 	destination, err := lease_set.Destination()
@@ -292,23 +293,109 @@ func (lease_set LeaseSet) Signature() (signature Signature, err error) {
 }
 
 //
+// Verify the Signature of this LeaseSet with the SigningPublicKey of its Destination, returning
+// nil if it is valid.  The signing_key field of the LeaseSet is not trusted for this, anyone can
+// put their own key there.
 //
+func (lease_set LeaseSet) Verify() (err error) {
+	signature, err := lease_set.Signature()
+	if err != nil {
+		return
+	}
+	destination, err := lease_set.Destination()
+	if err != nil {
+		return
+	}
+	signing_public_key, err := destination.SigningPublicKey()
+	if err != nil {
+		return
+	}
+	if signing_public_key == nil {
+		err = errors.New("error verifying lease set: unsupported signing key type")
+		return
+	}
+	verifier, err := signing_public_key.NewVerifier()
+	if err != nil {
+		return
+	}
+	err = verifier.Verify(lease_set[:lease_set.signedDataEnd()], signature)
+	return
+}
+
+//
+// Sign this LeaseSet with the Destination's signer, returning a copy of the LeaseSet with the
+// new Signature in place of any it had, or an error if the new Signature does not verify with
+// the Destination's SigningPublicKey.  The signer hashes the data with the digest of its
+// signing scheme, SHA-1 for DSA and SHA-512 for Ed25519, the same way Verify checks it.
 //
-func (lease_set LeaseSet) Verify() error {
-	//data_end := len(destination) +
-	//	LEASE_SET_PUBKEY_SIZE +
-	//	LEASE_SET_SPK_SIZE +
-	//	1 +
-	//	(44 * lease_set.LeaseCount())
-	//data := lease_set[:data_end]
-	//spk, _ := lease_set.
-	//	Destination().
-	//	SigningPublicKey()
-	//verifier, err := spk.NewVerifier()
-	//if err != nil {
-	//	return err
-	//}
-	return nil // verifier.Verify(data, lease_set.Signature())
+func (lease_set LeaseSet) Sign(signer crypto.Signer) (signed LeaseSet, err error) {
+	old_signature, err := lease_set.Signature()
+	if err != nil {
+		return
+	}
+	data_end := lease_set.signedDataEnd()
+	signature, err := signer.Sign(lease_set[:data_end])
+	if err != nil {
+		return
+	}
+	if len(signature) != len(old_signature) {
+		log.WithFields(log.Fields{
+			"at":            "(LeaseSet) Sign",
+			"signature_len": len(signature),
+			"expected_len":  len(old_signature),
+			"reason":        "signer does not match destination signing key type",
+		}).Error("error signing lease set")
+		err = crypto.ErrBadSignatureSize
+		return
+	}
+	signed = make(LeaseSet, 0, data_end+len(signature))
+	signed = append(signed, lease_set[:data_end]...)
+	signed = append(signed, signature...)
+	// a signer that does not own the Destination, or one of another scheme producing a
+	// Signature of the right size, e.g. Ed25519 for a P256 Destination, must be caught here
+	if err = signed.Verify(); err != nil {
+		log.WithFields(log.Fields{
+			"at":     "(LeaseSet) Sign",
+			"reason": err.Error(),
+		}).Error("signed lease set does not verify with destination signing key")
+		signed = nil
+	}
+	return
+}
+
+//
+// Return the LeaseSet in its wire form.
+//
+func (lease_set LeaseSet) Bytes() []byte {
+	return []byte(lease_set)
+}
+
+//
+// Read a LeaseSet from the start of data, sizing its keys and Signature by the
+// Destination's Key Certificate, and return it with the remaining bytes.
+//
+func ReadLeaseSet(data []byte) (lease_set LeaseSet, remainder []byte, err error) {
+	signature, err := LeaseSet(data).Signature()
+	if err != nil {
+		return
+	}
+	end := LeaseSet(data).signedDataEnd() + len(signature)
+	lease_set = LeaseSet(data[:end])
+	remainder = data[end:]
+	return
+}
+
+//
+// Return the length of the signed part of this LeaseSet, everything before the Signature.
+//
+func (lease_set LeaseSet) signedDataEnd() int {
+	destination, _ := lease_set.Destination()
+	lease_count, _ := lease_set.LeaseCount()
+	return len(destination) +
+		LEASE_SET_PUBKEY_SIZE +
+		LEASE_SET_SPK_SIZE +
+		1 +
+		(LEASE_SIZE * lease_count)
 }
 
 //
@@ -2,6 +2,11 @@ package common
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"github.com/go-i2p/go-i2p/lib/crypto"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -98,7 +103,7 @@ func TestSigningKeyIsCorrect(t *testing.T) {
 	lease_set := buildFullLeaseSet(1)
 	sk, err := lease_set.SigningKey()
 	if assert.Nil(err) {
-		assert.Equal(128, sk.Len())
+		assert.Equal(KEYCERT_SIGN_P256_SIZE, sk.Len())
 	}
 }
 
@@ -201,3 +206,177 @@ func TestOldestExpirationIsCorrect(t *testing.T) {
 		latest,
 	)
 }
+
+// the start of a LeaseSet up to its Signature for a Destination with an Ed25519 signing key,
+// with revocation_key in the unused signing_key field
+func buildEd25519LeaseSetData(n int, destination_key, revocation_key ed25519.PublicKey) []byte {
+	lease_set_data := make([]byte, 256+128-len(destination_key))
+	lease_set_data = append(lease_set_data, destination_key...)
+	lease_set_data = append(lease_set_data, []byte{0x05, 0x00, 0x04, 0x00, 0x07, 0x00, 0x00}...)
+	lease_set_data = append(lease_set_data, buildPublicKey()...)
+	signing_key := make([]byte, 128)
+	copy(signing_key[128-len(revocation_key):], revocation_key)
+	lease_set_data = append(lease_set_data, signing_key...)
+	lease_set_data = append(lease_set_data, byte(n))
+	lease_set_data = append(lease_set_data, buildLease(n)...)
+	return lease_set_data
+}
+
+func buildEd25519LeaseSet(n int) LeaseSet {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	lease_set_data := buildEd25519LeaseSetData(n, pub, nil)
+	signer, _ := crypto.Ed25519PrivateKey(priv).NewSigner()
+	sig, _ := signer.Sign(lease_set_data)
+	lease_set_data = append(lease_set_data, sig...)
+	return LeaseSet(lease_set_data)
+}
+
+func TestSigningKeyWithEd25519Destination(t *testing.T) {
+	assert := assert.New(t)
+
+	lease_set := buildEd25519LeaseSet(1)
+	sk, err := lease_set.SigningKey()
+	if assert.Nil(err) {
+		assert.IsType(crypto.Ed25519PublicKey{}, sk)
+		assert.Equal(KEYCERT_SIGN_ED25519_SIZE, sk.Len())
+	}
+}
+
+func TestVerifyWithEd25519Destination(t *testing.T) {
+	assert := assert.New(t)
+
+	lease_set := buildEd25519LeaseSet(2)
+	assert.Nil(lease_set.Verify())
+
+	lease_set[len(lease_set)-65] ^= 0xff
+	assert.NotNil(lease_set.Verify(), "LeaseSet with modified leases should not verify")
+}
+
+func TestVerifyIgnoresKeyInSigningKeyField(t *testing.T) {
+	assert := assert.New(t)
+
+	destination_key, _, _ := ed25519.GenerateKey(rand.Reader)
+	forger_key, forger_priv, _ := ed25519.GenerateKey(rand.Reader)
+	lease_set_data := buildEd25519LeaseSetData(1, destination_key, forger_key)
+	signer, _ := crypto.Ed25519PrivateKey(forger_priv).NewSigner()
+	sig, _ := signer.Sign(lease_set_data)
+	lease_set := LeaseSet(append(lease_set_data, sig...))
+
+	assert.NotNil(lease_set.Verify(), "LeaseSet signed by a key that is not the Destination's should not verify")
+}
+
+// an unsigned LeaseSet for a Destination with a null certificate and a DSA signing key
+func buildUnsignedDSALeaseSet(n int, dsa_key crypto.DSAPublicKey) LeaseSet {
+	lease_set_data := make([]byte, 256)
+	lease_set_data = append(lease_set_data, dsa_key[:]...)
+	lease_set_data = append(lease_set_data, []byte{0x00, 0x00, 0x00}...)
+	lease_set_data = append(lease_set_data, buildPublicKey()...)
+	lease_set_data = append(lease_set_data, make([]byte, 128)...)
+	lease_set_data = append(lease_set_data, byte(n))
+	lease_set_data = append(lease_set_data, buildLease(n)...)
+	lease_set_data = append(lease_set_data, make([]byte, 40)...)
+	return LeaseSet(lease_set_data)
+}
+
+func TestSignAndVerifyWithDSADestination(t *testing.T) {
+	assert := assert.New(t)
+
+	var priv crypto.DSAPrivateKey
+	for i := range priv {
+		priv[i] = byte(i + 1)
+	}
+	pub, err := priv.Public()
+	assert.Nil(err)
+	signer, err := priv.NewSigner()
+	assert.Nil(err)
+
+	unsigned := buildUnsignedDSALeaseSet(2, pub)
+	assert.NotNil(unsigned.Verify(), "unsigned LeaseSet should not verify")
+	lease_set, err := unsigned.Sign(signer)
+	assert.Nil(err)
+	assert.Equal(len(unsigned), len(lease_set))
+	assert.Nil(lease_set.Verify())
+
+	lease_set[len(lease_set)-41] ^= 0xff
+	assert.NotNil(lease_set.Verify(), "LeaseSet with modified leases should not verify")
+}
+
+func TestSignAndVerifyWithEd25519Destination(t *testing.T) {
+	assert := assert.New(t)
+
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := crypto.Ed25519PrivateKey(priv).NewSigner()
+
+	unsigned := LeaseSet(append(buildEd25519LeaseSetData(2, pub, nil), make([]byte, 64)...))
+	assert.NotNil(unsigned.Verify(), "unsigned LeaseSet should not verify")
+	lease_set, err := unsigned.Sign(signer)
+	assert.Nil(err)
+	assert.Nil(lease_set.Verify())
+}
+
+func TestSignWithSignerNotOwningDestination(t *testing.T) {
+	assert := assert.New(t)
+
+	destination_key, _, _ := ed25519.GenerateKey(rand.Reader)
+	signer_key, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := crypto.Ed25519PrivateKey(priv).NewSigner()
+
+	unsigned := LeaseSet(append(buildEd25519LeaseSetData(2, destination_key, signer_key), make([]byte, 64)...))
+	lease_set, err := unsigned.Sign(signer)
+	assert.NotNil(err, "a signer that does not own the Destination should be rejected")
+	assert.Nil(lease_set)
+}
+
+func TestSignWithWrongSchemeSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := crypto.Ed25519PrivateKey(priv).NewSigner()
+	_, err := buildUnsignedDSALeaseSet(1, crypto.DSAPublicKey{}).Sign(signer)
+	assert.Equal(crypto.ErrBadSignatureSize, err)
+}
+
+func TestSignWithWrongSchemeSignerOfSameSignatureSize(t *testing.T) {
+	assert := assert.New(t)
+
+	ec_priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	lease_set_data := make([]byte, 256+128-64)
+	lease_set_data = append(lease_set_data, paddedBytes(ec_priv.X, 32)...)
+	lease_set_data = append(lease_set_data, paddedBytes(ec_priv.Y, 32)...)
+	lease_set_data = append(lease_set_data, []byte{0x05, 0x00, 0x04, 0x00, 0x01, 0x00, 0x00}...)
+	lease_set_data = append(lease_set_data, buildPublicKey()...)
+	lease_set_data = append(lease_set_data, make([]byte, 128)...)
+	lease_set_data = append(lease_set_data, 0x01)
+	lease_set_data = append(lease_set_data, buildLease(1)...)
+	lease_set_data = append(lease_set_data, make([]byte, 64)...)
+
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := crypto.Ed25519PrivateKey(priv).NewSigner()
+	signed, err := LeaseSet(lease_set_data).Sign(signer)
+	assert.NotNil(err, "Ed25519 signature for a P256 destination should be rejected")
+	assert.Nil(signed)
+}
+
+func TestLeaseBytesRoundTrips(t *testing.T) {
+	assert := assert.New(t)
+
+	var lease Lease
+	copy(lease[:], buildLease(1))
+	again, remainder, err := ReadLease(append(lease.Bytes(), 0x01))
+	assert.Nil(err)
+	assert.Equal([]byte{0x01}, remainder)
+	assert.Equal(lease, again)
+
+	_, _, err = ReadLease(lease.Bytes()[:LEASE_SIZE-1])
+	assert.Equal(ERR_LEASE_NOT_ENOUGH_DATA, err)
+}
+
+func TestLeaseSetBytesRoundTrips(t *testing.T) {
+	assert := assert.New(t)
+
+	lease_set := buildFullLeaseSet(2)
+	again, remainder, err := ReadLeaseSet(append(lease_set.Bytes(), 0x01, 0x02))
+	assert.Nil(err)
+	assert.Equal([]byte{0x01, 0x02}, remainder)
+	assert.Equal(lease_set, again)
+}
@@ -32,6 +32,18 @@ import (
 	"sort"
 )
 
+// Minimum size of a valid Mapping, the 2 byte size field
+const (
+	MAPPING_MIN_SIZE = 2
+)
+
+// Errors returned by ReadMapping for each way a Mapping can be malformed
+var (
+	ERR_MAPPING_TOO_SHORT       = errors.New("error parsing mapping: mapping is too short")
+	ERR_MAPPING_STRING_OVERRUN  = errors.New("error parsing mapping: string length exceeds mapping data")
+	ERR_MAPPING_LENGTH_MISMATCH = errors.New("error parsing mapping: mapping length exceeds provided data")
+)
+
 type Mapping []byte
 
 // Parsed key-values pairs inside a Mapping.
@@ -45,29 +57,42 @@ func (mapping Mapping) Values() (map_values MappingValues, errs []error) {
 	var remainder = mapping
 	var err error
 
+	if len(mapping) < MAPPING_MIN_SIZE {
+		log.WithFields(log.Fields{
+			"at":                   "(Mapping) Values",
+			"mapping_bytes_length": len(mapping),
+			"reason":               "too short (len < MAPPING_MIN_SIZE)",
+		}).Error("invalid mapping")
+		errs = append(errs, ERR_MAPPING_TOO_SHORT)
+		return
+	}
 	length := Integer(remainder[:2])
 	inferred_length := length + 2
 	remainder = remainder[2:]
 	mapping_len := len(mapping)
 	if mapping_len > inferred_length {
-		log.WithFields(log.Fields{
+		parseLog.Warn(log.Fields{
 			"at":                    "(Mapping) Values",
 			"mappnig_bytes_length":  mapping_len,
 			"mapping_length_field":  length,
 			"expected_bytes_length": inferred_length,
 			"reason":                "data longer than expected",
-		}).Warn("mapping format warning")
+		}, "mapping format warning")
 		errs = append(errs, errors.New("warning parsing mapping: data exists beyond length of mapping"))
 	} else if inferred_length > mapping_len {
-		log.WithFields(log.Fields{
+		parseLog.Warn(log.Fields{
 			"at":                    "(Mapping) Values",
 			"mappnig_bytes_length":  mapping_len,
 			"mapping_length_field":  length,
 			"expected_bytes_length": inferred_length,
 			"reason":                "data shorter than expected",
-		}).Warn("mapping format warning")
+		}, "mapping format warning")
 		errs = append(errs, errors.New("warning parsing mapping: mapping length exceeds provided data"))
 	}
+	// an empty mapping is valid and has no pairs to read
+	if length == 0 {
+		return
+	}
 
 	for {
 		// Read a key, breaking on fatal errors
@@ -81,10 +106,10 @@ func (mapping Mapping) Values() (map_values MappingValues, errs []error) {
 			}
 		}
 		if !beginsWith(remainder, 0x3d) {
-			log.WithFields(log.Fields{
+			parseLog.Warn(log.Fields{
 				"at":     "(Mapping) Values",
 				"reason": "expected =",
-			}).Warn("mapping format violation")
+			}, "mapping format violation")
 			errs = append(errs, errors.New("mapping format violation, expected ="))
 			return
 		}
@@ -101,10 +126,10 @@ func (mapping Mapping) Values() (map_values MappingValues, errs []error) {
 			}
 		}
 		if !beginsWith(remainder, 0x3b) {
-			log.WithFields(log.Fields{
+			parseLog.Warn(log.Fields{
 				"at":     "(Mapping) Values",
 				"reason": "expected ;",
-			}).Warn("mapping format violation")
+			}, "mapping format violation")
 			errs = append(errs, errors.New("mapping format violation, expected ;"))
 			return
 		}
@@ -119,6 +144,118 @@ func (mapping Mapping) Values() (map_values MappingValues, errs []error) {
 	return
 }
 
+//
+// Read a Mapping from a slice of bytes, returning the Mapping, any remaining bytes and
+// one of ERR_MAPPING_TOO_SHORT, ERR_MAPPING_LENGTH_MISMATCH or ERR_MAPPING_STRING_OVERRUN
+// if the Mapping is malformed.  The string delimiters are not checked, use Values for that.
+//
+func ReadMapping(data []byte) (mapping Mapping, remainder []byte, err error) {
+	data_len := len(data)
+	if data_len < MAPPING_MIN_SIZE {
+		log.WithFields(log.Fields{
+			"at":                   "ReadMapping",
+			"mapping_bytes_length": data_len,
+			"reason":               "too short (len < MAPPING_MIN_SIZE)",
+		}).Error("invalid mapping")
+		err = ERR_MAPPING_TOO_SHORT
+		return
+	}
+	length := Integer(data[:MAPPING_MIN_SIZE])
+	inferred_length := length + MAPPING_MIN_SIZE
+	if inferred_length > data_len {
+		log.WithFields(log.Fields{
+			"at":                    "ReadMapping",
+			"mapping_bytes_length":  data_len,
+			"mapping_length_field":  length,
+			"expected_bytes_length": inferred_length,
+			"reason":                "data shorter than specified",
+		}).Error("invalid mapping")
+		err = ERR_MAPPING_LENGTH_MISMATCH
+		return
+	}
+	// each string is a length byte and its data, followed by a 1 byte delimiter
+	for pos := MAPPING_MIN_SIZE; pos < inferred_length; {
+		str_end := pos + 1 + Integer([]byte{data[pos]})
+		if str_end+1 > inferred_length {
+			log.WithFields(log.Fields{
+				"at":                    "ReadMapping",
+				"string_offset":         pos,
+				"mapping_length_field":  length,
+				"expected_bytes_length": str_end + 1,
+				"reason":                "string overruns mapping",
+			}).Error("invalid mapping")
+			err = ERR_MAPPING_STRING_OVERRUN
+			return
+		}
+		pos = str_end + 1
+	}
+	mapping = Mapping(data[:inferred_length])
+	remainder = data[inferred_length:]
+	return
+}
+
+//
+// Return the Mapping in its wire form, the 2 byte size followed by its pairs.
+//
+func (mapping Mapping) Bytes() []byte {
+	return []byte(mapping)
+}
+
+//
+// Call visit with each key and value in the Mapping in order, without building
+// MappingValues, until visit returns false.  Iteration stops quietly at the first
+// malformed pair; use Values to learn what is wrong with a Mapping.
+//
+func (mapping Mapping) Iterate(visit func(key, value string) bool) {
+	if len(mapping) < MAPPING_MIN_SIZE {
+		return
+	}
+	end := Integer(mapping[:MAPPING_MIN_SIZE]) + MAPPING_MIN_SIZE
+	if end > len(mapping) {
+		end = len(mapping)
+	}
+	data := mapping[MAPPING_MIN_SIZE:end]
+	for len(data) > 0 {
+		key, remainder, ok := readMappingField(data, 0x3d)
+		if !ok {
+			return
+		}
+		value, remainder, ok := readMappingField(remainder, 0x3b)
+		if !ok || !visit(key, value) {
+			return
+		}
+		data = remainder
+	}
+}
+
+//
+// Read a length prefixed string followed by delimiter from the start of data.
+//
+func readMappingField(data []byte, delimiter byte) (field string, remainder []byte, ok bool) {
+	if len(data) < 1 {
+		return
+	}
+	field_end := 1 + int(data[0])
+	if len(data) < field_end+1 || data[field_end] != delimiter {
+		return
+	}
+	return string(data[1:field_end]), data[field_end+1:], true
+}
+
+//
+// Return the value String stored under key in the MappingValues, or nil if
+// no pair with that key is present.
+//
+func (map_values MappingValues) Get(key string) String {
+	for _, pair := range map_values {
+		pair_key, _ := pair[0].Data()
+		if pair_key == key {
+			return pair[1]
+		}
+	}
+	return nil
+}
+
 //
 // Return true if two keys in a mapping are identical.
 //
@@ -185,3 +185,118 @@ func TestBeginsWithCorrectWhenNil(t *testing.T) {
 
 	assert.Equal(beginsWith(slice, 0x41), false, "beginsWith() did not return false on empty slice")
 }
+
+func TestReadMappingErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"empty", []byte{}, ERR_MAPPING_TOO_SHORT},
+		{"partial size", []byte{0x00}, ERR_MAPPING_TOO_SHORT},
+		{"size exceeds data", []byte{0x00, 0x08, 0x01, 0x61, 0x3d, 0x01, 0x62, 0x3b}, ERR_MAPPING_LENGTH_MISMATCH},
+		{"key overruns", []byte{0x00, 0x03, 0x05, 0x61, 0x3d}, ERR_MAPPING_STRING_OVERRUN},
+		{"value overruns", []byte{0x00, 0x06, 0x01, 0x61, 0x3d, 0x04, 0x62, 0x3b}, ERR_MAPPING_STRING_OVERRUN},
+		{"missing delimiter", []byte{0x00, 0x05, 0x01, 0x61, 0x3d, 0x01, 0x62}, ERR_MAPPING_STRING_OVERRUN},
+		{"valid", []byte{0x00, 0x06, 0x01, 0x61, 0x3d, 0x01, 0x62, 0x3b}, nil},
+		{"valid empty", []byte{0x00, 0x00}, nil},
+	}
+	for _, test := range tests {
+		_, _, err := ReadMapping(test.data)
+		assert.Equal(test.err, err, test.name)
+	}
+}
+
+func TestReadMappingReturnsRemainder(t *testing.T) {
+	assert := assert.New(t)
+
+	data := []byte{0x00, 0x06, 0x01, 0x61, 0x3d, 0x01, 0x62, 0x3b, 0x01, 0x02}
+	mapping, remainder, err := ReadMapping(data)
+	assert.Nil(err)
+	assert.Equal(Mapping(data[:8]), mapping)
+	assert.Equal([]byte{0x01, 0x02}, remainder)
+}
+
+func TestValuesReportsShortMapping(t *testing.T) {
+	assert := assert.New(t)
+
+	_, errs := Mapping([]byte{0x00}).Values()
+	assert.Equal([]error{ERR_MAPPING_TOO_SHORT}, errs)
+}
+
+func TestReadMappingWithZeroLength(t *testing.T) {
+	assert := assert.New(t)
+
+	data := []byte{0x00, 0x00, 0x01, 0x02, 0x03}
+	mapping, remainder, err := ReadMapping(data)
+	assert.Nil(err)
+	assert.Equal(Mapping{0x00, 0x00}, mapping)
+	assert.Equal([]byte{0x01, 0x02, 0x03}, remainder)
+
+	values, errs := mapping.Values()
+	assert.Empty(values)
+	assert.Empty(errs, "an empty mapping is valid")
+}
+
+func TestRouterInfoWithEmptyOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildRouterInfo(buildRouterIdentity(), nil, map[string]string{})
+	options := router_info.Options()
+	assert.Equal(Mapping{0x00, 0x00}, options)
+	values, errs := options.Values()
+	assert.Empty(values)
+	assert.Empty(errs)
+}
+
+func TestMappingBytesRoundTrips(t *testing.T) {
+	assert := assert.New(t)
+
+	mapping, _ := GoMapToMapping(map[string]string{"a": "b", "caps": "LR"})
+	again, remainder, err := ReadMapping(mapping.Bytes())
+	assert.Nil(err)
+	assert.Empty(remainder)
+	assert.Equal(mapping, again)
+}
+
+func TestIterateVisitsPairsInOrderAndStopsEarly(t *testing.T) {
+	assert := assert.New(t)
+
+	mapping := Mapping([]byte{
+		0x00, 0x12,
+		0x01, 0x63, 0x3d, 0x01, 0x33, 0x3b,
+		0x01, 0x61, 0x3d, 0x01, 0x31, 0x3b,
+		0x01, 0x62, 0x3d, 0x00, 0x3b,
+	})
+	visited := []string{}
+	mapping.Iterate(func(key, value string) bool {
+		visited = append(visited, key+"="+value)
+		return true
+	})
+	assert.Equal([]string{"c=3", "a=1", "b="}, visited)
+
+	visited = visited[:0]
+	mapping.Iterate(func(key, value string) bool {
+		visited = append(visited, key)
+		return key != "a"
+	})
+	assert.Equal([]string{"c", "a"}, visited, "iteration should stop once visit returns false")
+}
+
+func TestIterateStopsAtMalformedPair(t *testing.T) {
+	assert := assert.New(t)
+
+	mapping := Mapping([]byte{0x00, 0x0c, 0x01, 0x61, 0x3d, 0x01, 0x62, 0x3b, 0x01, 0x63, 0x30, 0x01, 0x64, 0x3b})
+	visited := []string{}
+	mapping.Iterate(func(key, value string) bool {
+		visited = append(visited, key)
+		return true
+	})
+	assert.Equal([]string{"a"}, visited)
+	Mapping(nil).Iterate(func(key, value string) bool {
+		t.Fatal("empty mapping has no pairs")
+		return false
+	})
+}
@@ -0,0 +1,16 @@
+package common
+
+// I2P network identifiers as published in the netId RouterInfo option
+const (
+	ROUTER_INFO_OPTION_NET_ID = "netId"
+	NET_ID_MAIN               = 2
+	NET_ID_TEST               = 3
+)
+
+// Transport styles as published in RouterAddresses
+const (
+	TRANSPORT_STYLE_NTCP  = "NTCP"
+	TRANSPORT_STYLE_NTCP2 = "NTCP2"
+	TRANSPORT_STYLE_SSU   = "SSU"
+	TRANSPORT_STYLE_SSU2  = "SSU2"
+)
@@ -0,0 +1,19 @@
+package common
+
+import (
+	"github.com/go-i2p/go-i2p/lib/util"
+)
+
+//
+// Warnings about malformed structures that peers can send us as often as they like
+// go through parseLog, so that a flood of bad data does not become a flood of logs.
+//
+var parseLog = util.NewRateLimitedLog(util.DEFAULT_RATE_LIMIT_INTERVAL)
+
+//
+// Register the parser warning log with a Reaper, so that warnings held back during a
+// flood of bad data are summarized once it has passed.
+//
+func RegisterParseLog(reaper *util.Reaper) {
+	reaper.Register("parse warnings", parseLog)
+}
@@ -47,6 +47,25 @@ const (
 
 type RouterAddress []byte
 
+//
+// Build a RouterAddress from its cost, expiration, transport style and options.
+//
+func NewRouterAddress(cost int, expiration Date, transport_style string, options map[string]string) (router_address RouterAddress, err error) {
+	style, err := ToI2PString(transport_style)
+	if err != nil {
+		return
+	}
+	mapping, err := GoMapToMapping(options)
+	if err != nil {
+		return
+	}
+	router_address = RouterAddress{byte(cost)}
+	router_address = append(router_address, expiration[:]...)
+	router_address = append(router_address, style...)
+	router_address = append(router_address, mapping...)
+	return
+}
+
 //
 // Return the cost integer for this RouterAddress and any errors encountered
 // parsing the RouterAddress.
@@ -103,6 +122,13 @@ func (router_address RouterAddress) Options() (mapping Mapping, err error) {
 	return
 }
 
+//
+// Return the RouterAddress in its wire form.
+//
+func (router_address RouterAddress) Bytes() []byte {
+	return []byte(router_address)
+}
+
 //
 // Check if the RouterAddress is empty or if it is too small to contain valid data.
 //
@@ -117,10 +143,10 @@ func (router_address RouterAddress) checkValid() (err error, exit bool) {
 		err = errors.New("error parsing RouterAddress: no data")
 		exit = true
 	} else if addr_len < ROUTER_ADDRESS_MIN_SIZE {
-		log.WithFields(log.Fields{
+		parseLog.Warn(log.Fields{
 			"at":     "(RouterAddress) checkValid",
 			"reason": "data too small (len < ROUTER_ADDRESS_MIN_SIZE)",
-		}).Warn("router address format warning")
+		}, "router address format warning")
 		err = errors.New("warning parsing RouterAddress: data too small")
 	}
 	return
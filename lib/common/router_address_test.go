@@ -95,3 +95,38 @@ func TestCorrectsFuzzCrasher1(t *testing.T) {
 	router_address_bytes := []byte{0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x00, 0x30, 0x30}
 	ReadRouterAddress(router_address_bytes)
 }
+
+func TestRouterAddressBytesRoundTrips(t *testing.T) {
+	assert := assert.New(t)
+
+	router_address := buildHostRouterAddress(10, "NTCP2", "192.168.1.1")
+	again, remainder, err := ReadRouterAddress(router_address.Bytes())
+	assert.Nil(err)
+	assert.Empty(remainder)
+	assert.Equal(router_address, again)
+}
+
+func TestNewRouterAddressReadsBack(t *testing.T) {
+	assert := assert.New(t)
+
+	expiration := Date{0x00, 0x00, 0x00, 0x00, 0x05, 0x26, 0x5c, 0x00}
+	router_address, err := NewRouterAddress(6, expiration, "NTCP2", map[string]string{"host": "127.0.0.1", "port": "4567"})
+	assert.Nil(err)
+	cost, err := router_address.Cost()
+	assert.Nil(err)
+	assert.Equal(6, cost)
+	date, err := router_address.Expiration()
+	assert.Nil(err)
+	assert.Equal(expiration, date)
+	style, err := router_address.TransportStyle()
+	assert.Nil(err)
+	style_data, _ := style.Data()
+	assert.Equal("NTCP2", style_data)
+	options, err := router_address.Options()
+	assert.Nil(err)
+	assert.Equal(buildMapping(), options)
+	again, remainder, err := ReadRouterAddress(router_address.Bytes())
+	assert.Nil(err)
+	assert.Empty(remainder)
+	assert.Equal(router_address, again)
+}
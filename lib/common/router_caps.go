@@ -0,0 +1,111 @@
+package common
+
+/*
+I2P RouterInfo Capabilities
+https://geti2p.net/spec/common-structures#routerinfo
+Accurate for version 0.9.24
+
+A router publishes its capabilities as a string of single character flags in
+the "caps" option of its RouterInfo, among them:
+
+f :: floodfill
+H :: hidden, the router does not publish its addresses
+R :: reachable, the router can be connected to directly
+U :: unreachable, the router cannot be connected to directly
+*/
+
+import (
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"strings"
+)
+
+// RouterInfo option key holding the capabilities
+const ROUTER_INFO_CAPS_OPTION = "caps"
+
+// Capability flags
+const (
+	CAPS_FLOODFILL   = 'f'
+	CAPS_HIDDEN      = 'H'
+	CAPS_REACHABLE   = 'R'
+	CAPS_UNREACHABLE = 'U'
+)
+
+var ERR_CAPS_HIDDEN_FLOODFILL = errors.New("router info is both hidden and floodfill")
+var ERR_CAPS_UNREACHABLE_FLOODFILL = errors.New("router info is both unreachable and floodfill")
+var ERR_CAPS_REACHABLE_AND_UNREACHABLE = errors.New("router info is both reachable and unreachable")
+var ERR_CAPS_REACHABLE_WITHOUT_ADDRESS = errors.New("router info is reachable but publishes no address")
+
+//
+// Return the capabilities string published in the caps option of this RouterInfo,
+// or an empty string if it has none.
+//
+func (router_info RouterInfo) Capabilities() (caps string) {
+	values, _ := router_info.Options().Values()
+	if value := values.Get(ROUTER_INFO_CAPS_OPTION); value != nil {
+		caps, _ = value.Data()
+	}
+	return
+}
+
+//
+// Return true if this RouterInfo publishes the given capability flag.
+//
+func (router_info RouterInfo) HasCapability(flag rune) bool {
+	return strings.ContainsRune(router_info.Capabilities(), flag)
+}
+
+//
+// Return true if this RouterInfo claims to be a floodfill.
+//
+func (router_info RouterInfo) IsFloodfill() bool {
+	return router_info.HasCapability(CAPS_FLOODFILL)
+}
+
+//
+// Return true if this RouterInfo claims to be hidden.
+//
+func (router_info RouterInfo) IsHidden() bool {
+	return router_info.HasCapability(CAPS_HIDDEN)
+}
+
+//
+// Return true if this RouterInfo claims to be reachable.
+//
+func (router_info RouterInfo) IsReachable() bool {
+	return router_info.HasCapability(CAPS_REACHABLE)
+}
+
+//
+// Check that the capabilities of this RouterInfo do not contradict each other or the
+// rest of the RouterInfo, returning an error describing the first contradiction found.
+// A floodfill must be reachable and not hidden, and a reachable router must publish at
+// least one RouterAddress.
+//
+func (router_info RouterInfo) CheckCapabilities() (err error) {
+	caps := router_info.Capabilities()
+	floodfill := strings.ContainsRune(caps, CAPS_FLOODFILL)
+	hidden := strings.ContainsRune(caps, CAPS_HIDDEN)
+	reachable := strings.ContainsRune(caps, CAPS_REACHABLE)
+	unreachable := strings.ContainsRune(caps, CAPS_UNREACHABLE)
+	switch {
+	case floodfill && hidden:
+		err = ERR_CAPS_HIDDEN_FLOODFILL
+	case floodfill && unreachable:
+		err = ERR_CAPS_UNREACHABLE_FLOODFILL
+	case reachable && unreachable:
+		err = ERR_CAPS_REACHABLE_AND_UNREACHABLE
+	case reachable:
+		if count, _ := router_info.RouterAddressCount(); count == 0 {
+			err = ERR_CAPS_REACHABLE_WITHOUT_ADDRESS
+		}
+	}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"at":     "(RouterInfo) CheckCapabilities",
+			"caps":   caps,
+			"reason": err.Error(),
+		}).Warn("contradictory router info capabilities")
+	}
+	return
+}
@@ -0,0 +1,43 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCapabilitiesFlags(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildRouterInfo(buildRouterIdentity(), []RouterAddress{buildRouterAddress("NTCP2")}, map[string]string{"caps": "fOR"})
+	assert.Equal("fOR", router_info.Capabilities())
+	assert.True(router_info.IsFloodfill())
+	assert.True(router_info.IsReachable())
+	assert.False(router_info.IsHidden())
+}
+
+func TestCheckCapabilities(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		caps      string
+		addresses int
+		err       error
+	}{
+		{"fOR", 1, nil},
+		{"LU", 0, nil},
+		{"H", 0, nil},
+		{"", 0, nil},
+		{"fH", 0, ERR_CAPS_HIDDEN_FLOODFILL},
+		{"fOU", 1, ERR_CAPS_UNREACHABLE_FLOODFILL},
+		{"LRU", 1, ERR_CAPS_REACHABLE_AND_UNREACHABLE},
+		{"LR", 0, ERR_CAPS_REACHABLE_WITHOUT_ADDRESS},
+	}
+	for _, test := range tests {
+		addresses := make([]RouterAddress, test.addresses)
+		for i := range addresses {
+			addresses[i] = buildRouterAddress("NTCP2")
+		}
+		router_info := buildRouterInfo(buildRouterIdentity(), addresses, map[string]string{"caps": test.caps})
+		assert.Equal(test.err, router_info.CheckCapabilities(), "caps "+test.caps)
+	}
+}
@@ -9,9 +9,15 @@ Identical to KeysAndCert
 */
 
 import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common/base64"
 	"github.com/go-i2p/go-i2p/lib/crypto"
+	"strings"
+	"unicode"
 )
 
+var ERR_BASE64_TRAILING_DATA = errors.New("base64 string contains data after the structure it encodes")
+
 //
 // A RouterIdentity is identical to KeysAndCert.
 //
@@ -21,6 +27,10 @@ func (router_identity RouterIdentity) PublicKey() (crypto.PublicKey, error) {
 	return KeysAndCert(router_identity).PublicKey()
 }
 
+func (router_identity RouterIdentity) EncryptionKey() (crypto.PublicKey, error) {
+	return KeysAndCert(router_identity).EncryptionKey()
+}
+
 func (router_identity RouterIdentity) SigningPublicKey() (crypto.SigningPublicKey, error) {
 	return KeysAndCert(router_identity).SigningPublicKey()
 }
@@ -34,3 +44,33 @@ func ReadRouterIdentity(data []byte) (router_identity RouterIdentity, remainder
 	router_identity = RouterIdentity(keys_and_cert)
 	return
 }
+
+//
+// Decode an I2P base64 string that may have been pasted from elsewhere, such as the
+// Java router console, ignoring any whitespace or line breaks inside it.
+//
+func decodePastedBase64(str string) ([]byte, error) {
+	return base64.DecodeFromString(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, str))
+}
+
+//
+// Parse a RouterIdentity from its I2P base64 encoding, as copied from the router console.
+// Surrounding and embedded whitespace is ignored, any bytes after the RouterIdentity
+// cause ERR_BASE64_TRAILING_DATA.
+//
+func RouterIdentityFromBase64(str string) (router_identity RouterIdentity, err error) {
+	data, err := decodePastedBase64(str)
+	if err != nil {
+		return
+	}
+	router_identity, remainder, err := ReadRouterIdentity(data)
+	if err == nil && len(remainder) > 0 {
+		err = ERR_BASE64_TRAILING_DATA
+	}
+	return
+}
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common/base64"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+// wrap a base64 string the way it appears when pasted from the router console
+func pasteBase64(data []byte) string {
+	encoded := base64.EncodeToString(data)
+	lines := []string{}
+	for len(encoded) > 64 {
+		lines = append(lines, encoded[:64])
+		encoded = encoded[64:]
+	}
+	lines = append(lines, encoded)
+	return "\n  " + strings.Join(lines, "\r\n") + " \n"
+}
+
+func TestRouterIdentityFromBase64WithEmbeddedNewlines(t *testing.T) {
+	assert := assert.New(t)
+
+	identity := buildRouterIdentity()
+	router_identity, err := RouterIdentityFromBase64(pasteBase64(identity))
+	assert.Nil(err)
+	assert.Equal(identity, router_identity)
+	cert, err := router_identity.Certificate()
+	assert.Nil(err)
+	cert_type, _ := cert.Type()
+	assert.Equal(CERT_KEY, cert_type)
+}
+
+func TestRouterIdentityFromBase64WithTrailingData(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := RouterIdentityFromBase64(pasteBase64(append(buildRouterIdentity(), 0x01)))
+	assert.Equal(ERR_BASE64_TRAILING_DATA, err)
+}
+
+func TestRouterIdentityFromBase64WithInvalidCharacters(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := RouterIdentityFromBase64("not+base64/")
+	assert.NotNil(err)
+}
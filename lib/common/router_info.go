@@ -75,9 +75,14 @@ signature :: Signature
 
 import (
 	"errors"
+	"github.com/go-i2p/go-i2p/lib/crypto"
 	log "github.com/sirupsen/logrus"
+	"net"
+	"strings"
 )
 
+var ERR_ROUTER_INFO_DUPLICATE_ADDRESS = errors.New("router info contains duplicate router addresses")
+
 type RouterInfo []byte
 
 //
@@ -184,6 +189,101 @@ func (router_info RouterInfo) RouterAddresses() (router_addresses []RouterAddres
 	return
 }
 
+//
+// Return the RouterAddresses inside this RouterInfo whose transport style is style.
+// Addresses of styles we do not know are kept by RouterAddresses so the RouterInfo
+// stays intact, transports use this to pick out only the addresses they can dial.
+//
+func (router_info RouterInfo) RouterAddressesByStyle(style string) (router_addresses []RouterAddress) {
+	addresses, _ := router_info.RouterAddresses()
+	for _, address := range addresses {
+		transport_style, err := address.TransportStyle()
+		if err != nil {
+			continue
+		}
+		if style_str, _ := transport_style.Data(); style_str == style {
+			router_addresses = append(router_addresses, address)
+		}
+	}
+	return
+}
+
+//
+// Read the RouterAddresses inside this RouterInfo, dropping duplicates.  Two addresses are
+// duplicates if they have the same transport style and the same IP version, or no host, so
+// a dual-stack router keeps one IPv4 and one IPv6 address per transport.  Of a set of
+// duplicates the lowest cost address, or the first of equal cost, is kept.  Returns the
+// kept addresses in their original order and the dropped ones.
+//
+func (router_info RouterInfo) UniqueRouterAddresses() (unique, duplicates []RouterAddress, err error) {
+	addresses, err := router_info.RouterAddresses()
+	kept := make(map[string]int)
+	keep := make([]bool, len(addresses))
+	for idx, address := range addresses {
+		key := routerAddressKey(address)
+		prev, ok := kept[key]
+		if !ok {
+			kept[key] = idx
+			keep[idx] = true
+			continue
+		}
+		prev_cost, _ := addresses[prev].Cost()
+		cost, _ := address.Cost()
+		if cost < prev_cost {
+			keep[prev] = false
+			kept[key] = idx
+			keep[idx] = true
+		}
+	}
+	for idx, address := range addresses {
+		if keep[idx] {
+			unique = append(unique, address)
+		} else {
+			duplicates = append(duplicates, address)
+		}
+	}
+	return
+}
+
+//
+// Check that this RouterInfo does not publish duplicate RouterAddresses as defined by
+// UniqueRouterAddresses, returning ERR_ROUTER_INFO_DUPLICATE_ADDRESS if it does.
+//
+func (router_info RouterInfo) CheckRouterAddresses() (err error) {
+	_, duplicates, err := router_info.UniqueRouterAddresses()
+	if err == nil && len(duplicates) > 0 {
+		log.WithFields(log.Fields{
+			"at":         "(RouterInfo) CheckRouterAddresses",
+			"duplicates": len(duplicates),
+		}).Warn("router info has duplicate router addresses")
+		err = ERR_ROUTER_INFO_DUPLICATE_ADDRESS
+	}
+	return
+}
+
+//
+// The transport style and IP version of a RouterAddress, used to find duplicates.
+//
+func routerAddressKey(router_address RouterAddress) string {
+	style, _ := router_address.TransportStyle()
+	style_str, _ := style.Data()
+	family := "none"
+	if options, err := router_address.Options(); err == nil && len(options) > 0 {
+		values, _ := options.Values()
+		if host := values.Get("host"); host != nil {
+			host_str, _ := host.Data()
+			if ip := net.ParseIP(host_str); ip == nil {
+				family = "host:" + strings.ToLower(host_str)
+			} else if ip.To4() != nil {
+				family = "ipv4"
+			} else {
+				family = "ipv6"
+			}
+		}
+	}
+	return style_str + "/" + family
+}
+
 //
 // Return the PeerSize value, currently unused and always zero.
 //
@@ -199,23 +299,216 @@ func (router_info RouterInfo) PeerSize() int {
 func (router_info RouterInfo) Options() (mapping Mapping) {
 	head := router_info.optionsLocation()
 	size := head + router_info.optionsSize()
+	if size > len(router_info) {
+		// the Mapping reports its own length error when it is parsed
+		size = len(router_info)
+	}
 	mapping = Mapping(router_info[head:size])
 	return
 }
 
+//
+// Return the router.version value published in the Options of this RouterInfo,
+// or an empty string if the router did not publish one.
+//
+func (router_info RouterInfo) RouterVersion() (version string) {
+	values, _ := router_info.Options().Values()
+	if value := values.Get("router.version"); value != nil {
+		version, _ = value.Data()
+	}
+	return
+}
+
 //
 // Return the signature of this router info
 //
 func (router_info RouterInfo) Signature() (signature Signature) {
-	head := router_info.optionsLocation()
-	size := head + router_info.optionsSize()
-	ident, _ := router_info.RouterIdentity()
-	keyCert := KeyCertificate(ident)
-	sigSize := keyCert.SignatureSize()
+	size := router_info.signatureLocation()
+	sigSize := router_info.signatureSize()
+	if len(router_info) < size+sigSize {
+		log.WithFields(log.Fields{
+			"at":           "(RouterInfo) Signature",
+			"data_len":     len(router_info),
+			"required_len": size + sigSize,
+			"reason":       "not enough data",
+		}).Error("error parsing router info")
+		return
+	}
 	signature = Signature(router_info[size : size+sigSize])
 	return
 }
 
+//
+// Verify the signature of this RouterInfo with the SigningPublicKey of its RouterIdentity,
+// returning nil if the signature is valid.  A malformed or truncated RouterInfo is rejected
+// with the error from parsing it before any signature is checked.
+//
+func (router_info RouterInfo) Verify() (err error) {
+	if _, _, err = ReadRouterInfo(router_info); err != nil {
+		return
+	}
+	ident, err := router_info.RouterIdentity()
+	if err != nil {
+		return
+	}
+	signature := router_info.Signature()
+	if len(signature) == 0 {
+		err = errors.New("error verifying router info: missing signature")
+		return
+	}
+	signing_public_key, err := ident.SigningPublicKey()
+	if err != nil {
+		return
+	}
+	if signing_public_key == nil {
+		err = errors.New("error verifying router info: unsupported signing key type")
+		return
+	}
+	verifier, err := signing_public_key.NewVerifier()
+	if err != nil {
+		return
+	}
+	err = verifier.Verify(router_info[:router_info.signatureLocation()], signature)
+	return
+}
+
+//
+// Build a RouterInfo from its parts, ending in an all zero Signature of the size the
+// signing key type of router_identity needs.  Use Sign to sign it.
+//
+func NewRouterInfo(router_identity RouterIdentity, published Date, router_addresses []RouterAddress, options map[string]string) (router_info RouterInfo, err error) {
+	if len(router_addresses) > 255 {
+		err = errors.New("error building router info: too many router addresses")
+		return
+	}
+	mapping, err := GoMapToMapping(options)
+	if err != nil {
+		return
+	}
+	router_info = append(RouterInfo{}, router_identity...)
+	router_info = append(router_info, published[:]...)
+	router_info = append(router_info, byte(len(router_addresses)))
+	for _, router_address := range router_addresses {
+		router_info = append(router_info, router_address...)
+	}
+	// peer size, always zero
+	router_info = append(router_info, 0x00)
+	router_info = append(router_info, mapping...)
+	router_info = append(router_info, make([]byte, router_info.signatureSize())...)
+	return
+}
+
+//
+// Sign this RouterInfo with the signer of its RouterIdentity, returning a copy of the
+// RouterInfo with the new Signature in place of any it had, or an error if the new
+// Signature does not verify with the SigningPublicKey of the RouterIdentity.
+//
+func (router_info RouterInfo) Sign(signer crypto.Signer) (signed RouterInfo, err error) {
+	if _, _, err = ReadRouterInfo(router_info); err != nil {
+		return
+	}
+	data_end := router_info.signatureLocation()
+	signature, err := signer.Sign(router_info[:data_end])
+	if err != nil {
+		return
+	}
+	if len(signature) != router_info.signatureSize() {
+		log.WithFields(log.Fields{
+			"at":            "(RouterInfo) Sign",
+			"signature_len": len(signature),
+			"expected_len":  router_info.signatureSize(),
+			"reason":        "signer does not match router identity signing key type",
+		}).Error("error signing router info")
+		err = crypto.ErrBadSignatureSize
+		return
+	}
+	signed = make(RouterInfo, 0, data_end+len(signature))
+	signed = append(signed, router_info[:data_end]...)
+	signed = append(signed, signature...)
+	if err = signed.Verify(); err != nil {
+		log.WithFields(log.Fields{
+			"at":     "(RouterInfo) Sign",
+			"reason": err.Error(),
+		}).Error("signed router info does not verify with router identity signing key")
+		signed = nil
+	}
+	return
+}
+
+//
+// Return a copy of the exact bytes of this RouterInfo as read from the wire, for re-flooding
+// and hashing.  Changes to the returned slice do not affect the RouterInfo.
+//
+func (router_info RouterInfo) RawBytes() (raw []byte) {
+	raw = make([]byte, len(router_info))
+	copy(raw, router_info)
+	return
+}
+
+//
+// Return the RouterInfo in its wire form.  Unlike RawBytes the slice is not copied.
+//
+func (router_info RouterInfo) Bytes() []byte {
+	return []byte(router_info)
+}
+
+//
+// Parse a RouterInfo from its I2P base64 encoding, as copied from the router console.
+// Surrounding and embedded whitespace is ignored, any bytes after the RouterInfo
+// cause ERR_BASE64_TRAILING_DATA.
+//
+func RouterInfoFromBase64(str string) (router_info RouterInfo, err error) {
+	data, err := decodePastedBase64(str)
+	if err != nil {
+		return
+	}
+	router_info, remainder, err := ReadRouterInfo(data)
+	if err == nil && len(remainder) > 0 {
+		err = ERR_BASE64_TRAILING_DATA
+	}
+	return
+}
+
+//
+// Read a RouterInfo from a slice of bytes, returning the RouterInfo, any remaining bytes and
+// any errors encountered parsing the RouterInfo.  The RouterInfo is a copy of the bytes read
+// rather than a slice of data, so later changes to data do not change it or its hash.
+//
+func ReadRouterInfo(data []byte) (router_info RouterInfo, remainder []byte, err error) {
+	cursor := NewCursor(data)
+	if _, err = cursor.ReadRouterIdentity(); err != nil {
+		return
+	}
+	if _, err = cursor.ReadDate(); err != nil {
+		return
+	}
+	addr_count, err := cursor.ReadInteger(1)
+	if err != nil {
+		return
+	}
+	for i := 0; i < addr_count; i++ {
+		if _, err = cursor.ReadRouterAddress(); err != nil {
+			return
+		}
+	}
+	// peer size, always zero
+	if _, err = cursor.ReadInteger(1); err != nil {
+		return
+	}
+	if _, err = cursor.ReadMapping(); err != nil {
+		return
+	}
+	sig_size := RouterInfo(data[:cursor.Offset()]).signatureSize()
+	if _, err = cursor.ReadBytes(sig_size); err != nil {
+		return
+	}
+	length := cursor.Offset()
+	router_info = make(RouterInfo, length)
+	copy(router_info, data[:length])
+	remainder = cursor.Remaining()
+	return
+}
+
 //
 // Used during parsing to determine where in the RouterInfo the Mapping data begins.
 //
@@ -263,6 +556,44 @@ func (router_info RouterInfo) optionsLocation() (location int) {
 //
 func (router_info RouterInfo) optionsSize() (size int) {
 	head := router_info.optionsLocation()
+	router_info_len := len(router_info)
+	if router_info_len < head+2 {
+		log.WithFields(log.Fields{
+			"at":           "(RouterInfo) optionsSize",
+			"data_len":     router_info_len,
+			"required_len": head + 2,
+			"reason":       "not enough data",
+		}).Error("error parsing router info")
+		return
+	}
 	size = Integer(router_info[head:head+2]) + 2
 	return
 }
+
+//
+// Used during parsing to determine where in the RouterInfo the Signature begins.
+//
+func (router_info RouterInfo) signatureLocation() (location int) {
+	location = router_info.optionsLocation() + router_info.optionsSize()
+	return
+}
+
+//
+// Used during parsing to determine the size of the Signature, as specified by the
+// Key Certificate of the RouterIdentity or the 40 bytes of a legacy DSA signature.
+//
+func (router_info RouterInfo) signatureSize() (size int) {
+	size = 40
+	ident, err := router_info.RouterIdentity()
+	if err != nil {
+		return
+	}
+	cert, err := ident.Certificate()
+	if err != nil {
+		return
+	}
+	if cert_type, _ := cert.Type(); cert_type == CERT_KEY {
+		size = KeyCertificate(cert).SignatureSize()
+	}
+	return
+}
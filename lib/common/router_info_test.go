@@ -2,7 +2,10 @@ package common
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
 	"fmt"
+	"github.com/go-i2p/go-i2p/lib/crypto"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -189,3 +192,197 @@ func TestRouterIdentityIsCorrect(t *testing.T) {
 		),
 	)
 }
+
+func buildRouterInfo(router_identity RouterIdentity, addresses []RouterAddress, options map[string]string) RouterInfo {
+	var published Date
+	copy(published[:], buildDate())
+	router_info, _ := NewRouterInfo(router_identity, published, addresses, options)
+	return router_info
+}
+
+func buildNullCertRouterInfo() RouterInfo {
+	keys_and_cert, _ := NewKeysAndCert(make([]byte, 256), make([]byte, 128), Certificate{CERT_NULL, 0x00, 0x00})
+	return buildRouterInfo(RouterIdentity(keys_and_cert), []RouterAddress{buildRouterAddress("foo")}, map[string]string{"host": "127.0.0.1", "port": "4567"})
+}
+
+func TestReadRouterInfoRawBytesMatchesInput(t *testing.T) {
+	assert := assert.New(t)
+
+	input := buildNullCertRouterInfo()
+	data := append([]byte(input), 0x01, 0x02)
+	router_info, remainder, err := ReadRouterInfo(data)
+	assert.Nil(err)
+	assert.Equal([]byte(input), router_info.RawBytes())
+	assert.Equal([]byte{0x01, 0x02}, remainder)
+}
+
+func TestReadRouterInfoSurvivesMutation(t *testing.T) {
+	assert := assert.New(t)
+
+	input := buildNullCertRouterInfo()
+	data := append([]byte{}, input...)
+	router_info, _, err := ReadRouterInfo(data)
+	assert.Nil(err)
+	hash, _ := router_info.IdentHash()
+
+	data[0] ^= 0xff
+	raw := router_info.RawBytes()
+	raw[1] ^= 0xff
+	assert.Equal([]byte(input), router_info.RawBytes(), "RouterInfo changed when its input or RawBytes were mutated")
+	after, _ := router_info.IdentHash()
+	assert.Equal(hash, after)
+}
+
+func TestReadRouterInfoTooShort(t *testing.T) {
+	assert := assert.New(t)
+
+	input := buildNullCertRouterInfo()
+	_, _, err := ReadRouterInfo(input[:len(input)-1])
+	assert.NotNil(err)
+}
+
+func buildHostRouterAddress(cost byte, transport, host string) RouterAddress {
+	router_address, _ := NewRouterAddress(int(cost), Date{}, transport, map[string]string{"host": host, "port": "4567"})
+	return router_address
+}
+
+func TestUniqueRouterAddressesKeepsLowestCostNTCP2(t *testing.T) {
+	assert := assert.New(t)
+
+	expensive := buildHostRouterAddress(10, "NTCP2", "192.168.1.1")
+	cheap := buildHostRouterAddress(5, "NTCP2", "10.0.0.1")
+	router_info := buildRouterInfo(buildRouterIdentity(), []RouterAddress{expensive, cheap}, nil)
+	unique, duplicates, err := router_info.UniqueRouterAddresses()
+	assert.Nil(err)
+	assert.Equal([]RouterAddress{cheap}, unique)
+	assert.Equal([]RouterAddress{expensive}, duplicates)
+	assert.Equal(ERR_ROUTER_INFO_DUPLICATE_ADDRESS, router_info.CheckRouterAddresses())
+}
+
+func TestUniqueRouterAddressesKeepsDualStack(t *testing.T) {
+	assert := assert.New(t)
+
+	ipv4 := buildHostRouterAddress(10, "NTCP2", "192.168.1.1")
+	ipv6 := buildHostRouterAddress(10, "NTCP2", "2001:db8::1")
+	ssu := buildHostRouterAddress(10, "SSU2", "192.168.1.1")
+	router_info := buildRouterInfo(buildRouterIdentity(), []RouterAddress{ipv4, ipv6, ssu}, nil)
+	unique, duplicates, err := router_info.UniqueRouterAddresses()
+	assert.Nil(err)
+	assert.Equal([]RouterAddress{ipv4, ipv6, ssu}, unique)
+	assert.Nil(duplicates)
+	assert.Nil(router_info.CheckRouterAddresses())
+}
+
+func TestRouterAddressesKeepsUnknownTransportStyle(t *testing.T) {
+	assert := assert.New(t)
+
+	known := buildHostRouterAddress(10, "NTCP2", "192.168.1.1")
+	unknown := buildHostRouterAddress(5, "NTCP3", "192.168.1.1")
+	router_info := buildRouterInfo(buildRouterIdentity(), []RouterAddress{unknown, known}, nil)
+	addresses, err := router_info.RouterAddresses()
+	assert.Nil(err)
+	assert.Equal([]RouterAddress{unknown, known}, addresses)
+	assert.Equal([]RouterAddress{known}, router_info.RouterAddressesByStyle("NTCP2"))
+	assert.Nil(router_info.RouterAddressesByStyle("SSU2"))
+}
+
+func TestRouterInfoFromBase64WithEmbeddedNewlines(t *testing.T) {
+	assert := assert.New(t)
+
+	input := buildNullCertRouterInfo()
+	router_info, err := RouterInfoFromBase64(pasteBase64(input))
+	assert.Nil(err)
+	assert.Equal(input, router_info)
+	count, err := router_info.RouterAddressCount()
+	assert.Nil(err)
+	assert.Equal(1, count)
+}
+
+func TestRouterInfoBytesRoundTrips(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildRouterInfo(buildRouterIdentity(), []RouterAddress{buildHostRouterAddress(10, "NTCP2", "192.168.1.1")}, nil)
+	again, remainder, err := ReadRouterInfo(router_info.Bytes())
+	assert.Nil(err)
+	assert.Empty(remainder)
+	assert.Equal(router_info, again)
+}
+
+func TestVerifyRejectsTruncatedRouterInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	input := buildNullCertRouterInfo()
+	for length := 0; length < len(input); length++ {
+		// copy so that reading past the end cannot succeed within the capacity of input
+		truncated := make(RouterInfo, length)
+		copy(truncated, input)
+		assert.NotPanics(func() {
+			assert.NotNil(truncated.Verify())
+		}, "Verify panicked on %d bytes", length)
+	}
+}
+
+func TestOptionsOfTruncatedRouterInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	input := buildNullCertRouterInfo()
+	identity_only := make(RouterInfo, 387)
+	copy(identity_only, input)
+	short_options := make(RouterInfo, len(input)-40-2)
+	copy(short_options, input)
+	assert.NotPanics(func() {
+		identity_only.Options()
+		short_options.Options()
+	})
+}
+
+func buildEd25519RouterIdentity(pub ed25519.PublicKey) RouterIdentity {
+	keys_and_cert, _ := NewKeysAndCert(make([]byte, 32), pub, Certificate(NewKeyCertificate(KEYCERT_SIGN_ED25519, KEYCERT_CRYPTO_X25519)))
+	return RouterIdentity(keys_and_cert)
+}
+
+func TestNewRouterInfoReadsBack(t *testing.T) {
+	assert := assert.New(t)
+
+	router_address := buildRouterAddress("NTCP2")
+	router_info := buildRouterInfo(buildRouterIdentity(), []RouterAddress{router_address}, map[string]string{"caps": "LR"})
+	again, remainder, err := ReadRouterInfo(router_info)
+	assert.Nil(err)
+	assert.Empty(remainder)
+	assert.Equal(router_info, again)
+	addresses, err := router_info.RouterAddresses()
+	assert.Nil(err)
+	assert.Equal([]RouterAddress{router_address}, addresses)
+	assert.Equal("LR", router_info.Capabilities())
+	assert.Equal(Signature(make([]byte, 64)), router_info.Signature())
+}
+
+func TestSignProducesVerifiedRouterInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := crypto.Ed25519PrivateKey(priv).NewSigner()
+	unsigned := buildRouterInfo(buildEd25519RouterIdentity(pub), nil, map[string]string{"caps": "L"})
+	assert.NotNil(unsigned.Verify())
+	router_info, err := unsigned.Sign(signer)
+	assert.Nil(err)
+	assert.Nil(router_info.Verify())
+	assert.Equal(len(unsigned), len(router_info))
+	assert.Equal("L", router_info.Capabilities())
+}
+
+func TestSignRejectsSignerNotOwningRouterIdentity(t *testing.T) {
+	assert := assert.New(t)
+
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	_, other, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := crypto.Ed25519PrivateKey(other).NewSigner()
+	router_info, err := buildRouterInfo(buildEd25519RouterIdentity(pub), nil, nil).Sign(signer)
+	assert.NotNil(err)
+	assert.Nil(router_info)
+
+	// a null certificate means DSA_SHA1 and its 40 byte Signatures
+	router_info, err = buildNullCertRouterInfo().Sign(signer)
+	assert.Equal(crypto.ErrBadSignatureSize, err)
+	assert.Nil(router_info)
+}
@@ -0,0 +1,55 @@
+package common
+
+/*
+I2P RouterInfo Statistics
+https://geti2p.net/spec/common-structures#routerinfo
+Accurate for version 0.9.24
+
+Routers may publish statistics in their RouterInfo options under keys beginning
+with "stat_", such as "stat_bandwidthSendBps60s".  These are informational only
+and are not used by the network.
+*/
+
+import (
+	"strings"
+)
+
+// Prefix of RouterInfo option keys holding statistics
+const ROUTER_INFO_STAT_PREFIX = "stat_"
+
+//
+// The statistics published by a RouterInfo, keyed by their name without the
+// ROUTER_INFO_STAT_PREFIX.
+//
+type RouterInfoStats map[string]string
+
+//
+// Return all of the statistics published in the options of this RouterInfo.
+//
+func (router_info RouterInfo) Stats() (stats RouterInfoStats) {
+	stats = make(RouterInfoStats)
+	values, _ := router_info.Options().Values()
+	for _, pair := range values {
+		key, err := pair[0].Data()
+		if err != nil || !strings.HasPrefix(key, ROUTER_INFO_STAT_PREFIX) {
+			continue
+		}
+		value, _ := pair[1].Data()
+		stats[strings.TrimPrefix(key, ROUTER_INFO_STAT_PREFIX)] = value
+	}
+	return
+}
+
+//
+// Return the RouterInfo options for the statistics named in publish, so a router can
+// choose which of its statistics it publishes.  Names that are not in stats are skipped.
+//
+func (stats RouterInfoStats) Options(publish []string) (options map[string]string) {
+	options = make(map[string]string)
+	for _, name := range publish {
+		if value, ok := stats[name]; ok {
+			options[ROUTER_INFO_STAT_PREFIX+name] = value
+		}
+	}
+	return
+}
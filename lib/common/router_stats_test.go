@@ -0,0 +1,46 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRouterInfoStatsCollectsStatOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildRouterInfo(buildRouterIdentity(), nil, map[string]string{
+		"caps":                         "LR",
+		"router.version":               "0.9.50",
+		"stat_bandwidthSendBps60s":     "1000",
+		"stat_bandwidthReceiveBps60s":  "2000",
+		"stat_tunnel.buildSuccessRate": "0.9",
+	})
+	assert.Equal(RouterInfoStats{
+		"bandwidthSendBps60s":     "1000",
+		"bandwidthReceiveBps60s":  "2000",
+		"tunnel.buildSuccessRate": "0.9",
+	}, router_info.Stats())
+}
+
+func TestRouterInfoStatsWithoutStats(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildRouterInfo(buildRouterIdentity(), nil, map[string]string{"caps": "LR"})
+	assert.Equal(0, len(router_info.Stats()))
+}
+
+func TestRouterInfoStatsOptionsPublishesSubset(t *testing.T) {
+	assert := assert.New(t)
+
+	stats := RouterInfoStats{
+		"bandwidthSendBps60s":    "1000",
+		"bandwidthReceiveBps60s": "2000",
+	}
+	options := stats.Options([]string{"bandwidthSendBps60s", "missing"})
+	assert.Equal(map[string]string{"stat_bandwidthSendBps60s": "1000"}, options)
+
+	options["caps"] = "LR"
+	router_info := buildRouterInfo(buildRouterIdentity(), nil, options)
+	assert.Equal(RouterInfoStats{"bandwidthSendBps60s": "1000"}, router_info.Stats())
+	assert.Equal("LR", router_info.Capabilities())
+}
@@ -35,22 +35,22 @@ func (str String) Length() (length int, err error) {
 	inferred_len := length + 1
 	str_len := len(str)
 	if inferred_len > str_len {
-		log.WithFields(log.Fields{
+		parseLog.Warn(log.Fields{
 			"at":                    "(String) Length",
 			"string_bytes_length":   str_len,
 			"string_length_field":   length,
 			"expected_bytes_length": inferred_len,
 			"reason":                "data shorter than specified",
-		}).Warn("string format warning")
+		}, "string format warning")
 		err = errors.New("string parsing warning: string data is shorter than specified by length")
 	} else if str_len > inferred_len {
-		log.WithFields(log.Fields{
+		parseLog.Warn(log.Fields{
 			"at":                    "(String) Length",
 			"string_bytes_length":   str_len,
 			"string_length_field":   length,
 			"expected_bytes_length": inferred_len,
 			"reason":                "data longer than specified",
-		}).Warn("string format warning")
+		}, "string format warning")
 		err = errors.New("string parsing warning: string contains data beyond length")
 	}
 	return
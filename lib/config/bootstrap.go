@@ -4,8 +4,10 @@ package config
 type ReseedConfig struct {
 	// url of reseed server
 	Url string
-	// fingerprint of reseed su3 signing key
+	// fingerprint of reseed su3 signing key, see SU3KeyFingerprint
 	SU3Fingerprint string
+	// PEM encoded X.509 certificate of the reseed su3 signing key
+	SU3Certificate string
 }
 
 type BootstrapConfig struct {
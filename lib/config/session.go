@@ -0,0 +1,12 @@
+package config
+
+// options of a client session
+type SessionConfig struct {
+	// names of the transports to dial peers with first when they offer more than one, in
+	// order of preference, i.e. SSU2 for latency sensitive traffic or NTCP2 for bulk transfers
+	// transports not listed are tried after these in the muxer's usual order
+	PreferredTransports []string
+}
+
+// default settings for client sessions, no transport preference
+var DefaultSessionConfig = SessionConfig{}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/util"
+	log "github.com/sirupsen/logrus"
+)
+
+var ERR_SU3_UNEXPECTED_CONTENT_TYPE = errors.New("su3 content type is not the expected content type")
+var ERR_SU3_UNEXPECTED_FILE_TYPE = errors.New("su3 file type is not the expected file type")
+
+// check that an su3 holds the content and file type the caller expects to handle,
+// i.e. reseeding expects SU3_CONTENT_TYPE_RESEED_DATA in a SU3_FILE_TYPE_ZIP
+// so a plugin or router update su3 is never unpacked as reseed data
+func (su3 SU3) CheckContent(content_type, file_type string) error {
+	if su3.ContentType != content_type {
+		log.WithFields(log.Fields{
+			"at":       "(SU3) CheckContent",
+			"expected": content_type,
+			"got":      su3.ContentType,
+		}).Warn(ERR_SU3_UNEXPECTED_CONTENT_TYPE)
+		return ERR_SU3_UNEXPECTED_CONTENT_TYPE
+	}
+	if su3.FileType != file_type {
+		log.WithFields(log.Fields{
+			"at":       "(SU3) CheckContent",
+			"expected": file_type,
+			"got":      su3.FileType,
+		}).Warn(ERR_SU3_UNEXPECTED_FILE_TYPE)
+		return ERR_SU3_UNEXPECTED_FILE_TYPE
+	}
+	return nil
+}
+
+// the Version of this su3 parsed for comparison
+// reseed bundles use a unix timestamp as their version, router updates a dotted
+// version such as 0.9.50
+func (su3 SU3) ParsedVersion() (util.Version, error) {
+	return util.ParseVersion(su3.Version)
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"encoding/binary"
+	"github.com/go-i2p/go-i2p/lib/util"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// a complete su3 as a reseed server would serve it, with a dummy signature
+func buildSU3(file_type, content_type byte, version string, content []byte) []byte {
+	version_bytes := make([]byte, 16)
+	copy(version_bytes, version)
+	signer_id := []byte("reseed@mail.i2p")
+	signature := make([]byte, 64)
+
+	su3 := []byte(SU3_MAGIC_BYTES)
+	su3 = append(su3, 0x00, 0x00)
+	su3 = append(su3, 0x00, 0x01)
+	su3 = append(su3, 0x00, byte(len(signature)))
+	su3 = append(su3, 0x00, byte(len(version_bytes)))
+	su3 = append(su3, 0x00, byte(len(signer_id)))
+	content_length := make([]byte, 8)
+	binary.BigEndian.PutUint64(content_length, uint64(len(content)))
+	su3 = append(su3, content_length...)
+	su3 = append(su3, 0x00, file_type)
+	su3 = append(su3, 0x00, content_type)
+	su3 = append(su3, make([]byte, 12)...)
+	su3 = append(su3, version_bytes...)
+	su3 = append(su3, signer_id...)
+	su3 = append(su3, content...)
+	su3 = append(su3, signature...)
+	return su3
+}
+
+func TestReadSU3HeaderFields(t *testing.T) {
+	assert := assert.New(t)
+
+	su3, err := ReadSU3(buildSU3(0x00, 0x03, "1622435443", []byte("PK")))
+	assert.Nil(err)
+	assert.Equal(SU3_SIGNATURE_TYPE_ECDSA_SHA256_P256, su3.SignatureType)
+	assert.Equal(SU3_FILE_TYPE_ZIP, su3.FileType)
+	assert.Equal(SU3_CONTENT_TYPE_RESEED_DATA, su3.ContentType)
+	assert.Equal("1622435443", su3.Version)
+	assert.Equal("reseed@mail.i2p", su3.SignerID)
+	version, err := su3.ParsedVersion()
+	assert.Nil(err)
+	assert.Equal(util.Version{1622435443}, version)
+	assert.Nil(su3.CheckContent(SU3_CONTENT_TYPE_RESEED_DATA, SU3_FILE_TYPE_ZIP))
+}
+
+func TestSU3CheckContentRejectsPluginAsReseed(t *testing.T) {
+	assert := assert.New(t)
+
+	su3, err := ReadSU3(buildSU3(0x00, 0x02, "1.2.3", []byte("PK")))
+	assert.Nil(err)
+	assert.Equal(ERR_SU3_UNEXPECTED_CONTENT_TYPE, su3.CheckContent(SU3_CONTENT_TYPE_RESEED_DATA, SU3_FILE_TYPE_ZIP))
+
+	su3, err = ReadSU3(buildSU3(0x01, 0x03, "1.2.3", []byte("<xml/>")))
+	assert.Nil(err)
+	assert.Equal(ERR_SU3_UNEXPECTED_FILE_TYPE, su3.CheckContent(SU3_CONTENT_TYPE_RESEED_DATA, SU3_FILE_TYPE_ZIP))
+}
+
+func TestReadReseedSU3Header(t *testing.T) {
+	assert := assert.New(t)
+
+	// the header of an i2pseeds.su3 as reseed servers write it, without its content and signature
+	header, err := ioutil.ReadFile(filepath.Join("testdata", "reseed_su3_header.bin"))
+	if !assert.Nil(err) {
+		return
+	}
+	su3, err := ReadSU3(header)
+	assert.NotNil(err, "the content and signature are missing")
+	assert.Equal(SU3_SIGNATURE_TYPE_RSA_SHA512_4096, su3.SignatureType)
+	assert.Equal(512, su3.SignatureLength)
+	assert.Equal(72271, su3.ContentLength)
+	assert.Equal(SU3_FILE_TYPE_ZIP, su3.FileType)
+	assert.Equal(SU3_CONTENT_TYPE_RESEED_DATA, su3.ContentType)
+	assert.Equal("1622435443", su3.Version)
+	assert.Equal("hankhill19580@gmail.com", su3.SignerID)
+	assert.Nil(su3.CheckContent(SU3_CONTENT_TYPE_RESEED_DATA, SU3_FILE_TYPE_ZIP))
+
+	version, err := su3.ParsedVersion()
+	assert.Nil(err)
+	older, _ := util.ParseVersion("1622435000")
+	assert.Equal(1, version.Compare(older))
+}
+
+func TestSU3ParsedVersionRejectsNonNumericVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	su3, err := ReadSU3(buildSU3(0x00, 0x01, "0.9.50-rc1", []byte("PK")))
+	assert.Nil(err)
+	_, err = su3.ParsedVersion()
+	assert.Equal(util.ERR_VERSION_NOT_NUMERIC, err)
+}
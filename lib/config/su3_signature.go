@@ -0,0 +1,150 @@
+package config
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"math/big"
+	"strings"
+)
+
+// length of the fixed su3 header before the version
+const SU3_HEADER_LEN = 40
+
+var ERR_SU3_SIGNATURE_TYPE_UNSUPPORTED = errors.New("su3 signature type is not supported")
+var ERR_SU3_SIGNER_KEY_MISMATCH = errors.New("su3 signer key does not match the signature type")
+var ERR_SU3_SIGNATURE_INVALID = errors.New("su3 signature is invalid")
+var ERR_SU3_CERTIFICATE_INVALID = errors.New("su3 signing certificate is not a PEM encoded X.509 certificate")
+var ERR_SU3_FINGERPRINT_MISSING = errors.New("no su3 signing key fingerprint configured")
+var ERR_SU3_FINGERPRINT_MISMATCH = errors.New("su3 signing certificate does not match the configured fingerprint")
+
+// the bytes of an su3 covered by its signature, everything before the signature
+func (su3 SU3) SignedBytes() []byte {
+	end := SU3_HEADER_LEN + su3.VersionLength + su3.SignerIDLength + su3.ContentLength
+	if end > len(su3.Raw) {
+		end = len(su3.Raw)
+	}
+	return su3.Raw[:end]
+}
+
+// check the su3 signature with the public key of its signer, an *rsa.PublicKey or
+// *ecdsa.PublicKey as found in the signer's X.509 certificate
+func (su3 SU3) Verify(public_key crypto.PublicKey) (err error) {
+	var hash crypto.Hash
+	switch su3.SignatureType {
+	case SU3_SIGNATURE_TYPE_ECDSA_SHA256_P256, SU3_SIGNATURE_TYPE_RSA_SHA256_2048:
+		hash = crypto.SHA256
+	case SU3_SIGNATURE_TYPE_ECDSA_SHA384_P384, SU3_SIGNATURE_TYPE_RSA_SHA384_3072:
+		hash = crypto.SHA384
+	case SU3_SIGNATURE_TYPE_ECDSA_SHA512_P521, SU3_SIGNATURE_TYPE_RSA_SHA512_4096:
+		hash = crypto.SHA512
+	default:
+		err = ERR_SU3_SIGNATURE_TYPE_UNSUPPORTED
+		return
+	}
+	h := hash.New()
+	h.Write(su3.SignedBytes())
+	digest := h.Sum(nil)
+
+	switch su3.SignatureType {
+	case SU3_SIGNATURE_TYPE_RSA_SHA256_2048, SU3_SIGNATURE_TYPE_RSA_SHA384_3072, SU3_SIGNATURE_TYPE_RSA_SHA512_4096:
+		err = verifyRSA(su3.SignatureType, public_key, hash, digest, su3.Signature)
+	default:
+		err = verifyECDSA(su3.SignatureType, public_key, digest, su3.Signature)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"at":             "(SU3) Verify",
+			"signature_type": su3.SignatureType,
+			"signer_id":      su3.SignerID,
+			"reason":         err.Error(),
+		}).Warn("su3 signature check failed")
+	}
+	return
+}
+
+func verifyRSA(signature_type string, public_key crypto.PublicKey, hash crypto.Hash, digest, sig []byte) error {
+	bits := map[string]int{
+		SU3_SIGNATURE_TYPE_RSA_SHA256_2048: 2048,
+		SU3_SIGNATURE_TYPE_RSA_SHA384_3072: 3072,
+		SU3_SIGNATURE_TYPE_RSA_SHA512_4096: 4096,
+	}[signature_type]
+	key, ok := public_key.(*rsa.PublicKey)
+	if !ok || key.N.BitLen() != bits {
+		return ERR_SU3_SIGNER_KEY_MISMATCH
+	}
+	if rsa.VerifyPKCS1v15(key, hash, digest, sig) != nil {
+		return ERR_SU3_SIGNATURE_INVALID
+	}
+	return nil
+}
+
+// ecdsa su3 signatures are r and s as fixed length big-endian integers
+func verifyECDSA(signature_type string, public_key crypto.PublicKey, digest, sig []byte) error {
+	curve := map[string]elliptic.Curve{
+		SU3_SIGNATURE_TYPE_ECDSA_SHA256_P256: elliptic.P256(),
+		SU3_SIGNATURE_TYPE_ECDSA_SHA384_P384: elliptic.P384(),
+		SU3_SIGNATURE_TYPE_ECDSA_SHA512_P521: elliptic.P521(),
+	}[signature_type]
+	key, ok := public_key.(*ecdsa.PublicKey)
+	if !ok || key.Curve != curve {
+		return ERR_SU3_SIGNER_KEY_MISMATCH
+	}
+	size := (curve.Params().BitSize + 7) / 8
+	if len(sig) != 2*size {
+		return ERR_SU3_SIGNATURE_INVALID
+	}
+	r := new(big.Int).SetBytes(sig[:size])
+	s := new(big.Int).SetBytes(sig[size:])
+	if !ecdsa.Verify(key, digest, r, s) {
+		return ERR_SU3_SIGNATURE_INVALID
+	}
+	return nil
+}
+
+// the fingerprint of an su3 signing key, the hex encoded SHA-256 of the DER encoded
+// SubjectPublicKeyInfo in its certificate
+func SU3KeyFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(sum[:])
+}
+
+// parse the reseed server's su3 signing certificate and return its public key
+// if the key matches SU3Fingerprint, so a certificate swapped in the config alone
+// is not trusted
+func (reseed *ReseedConfig) SigningKey() (public_key crypto.PublicKey, err error) {
+	fingerprint := strings.ToLower(strings.Replace(reseed.SU3Fingerprint, ":", "", -1))
+	if fingerprint == "" {
+		err = ERR_SU3_FINGERPRINT_MISSING
+		return
+	}
+	block, _ := pem.Decode([]byte(reseed.SU3Certificate))
+	if block == nil {
+		err = ERR_SU3_CERTIFICATE_INVALID
+		return
+	}
+	cert, cerr := x509.ParseCertificate(block.Bytes)
+	if cerr != nil {
+		err = ERR_SU3_CERTIFICATE_INVALID
+		return
+	}
+	if SU3KeyFingerprint(cert) != fingerprint {
+		log.WithFields(log.Fields{
+			"at":       "(ReseedConfig) SigningKey",
+			"url":      reseed.Url,
+			"expected": fingerprint,
+		}).Warn(ERR_SU3_FINGERPRINT_MISMATCH)
+		err = ERR_SU3_FINGERPRINT_MISMATCH
+		return
+	}
+	public_key = cert.PublicKey
+	return
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildRSASignedSU3(t *testing.T, priv *rsa.PrivateKey) SU3 {
+	version := make([]byte, 16)
+	signer_id := []byte("test@mail.i2p")
+	content := []byte("content")
+	data := []byte("I2Psu3")
+	data = append(data, 0x00, 0x00, 0x00, 0x06, 0x02, 0x00, 0x00, byte(len(version)), 0x00, byte(len(signer_id)))
+	data = append(data, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, byte(len(content)))
+	data = append(data, 0x00, 0x00, 0x00, 0x03)
+	data = append(data, make([]byte, 12)...)
+	data = append(data, version...)
+	data = append(data, signer_id...)
+	data = append(data, content...)
+	digest := sha512.Sum512(data)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA512, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	su3, err := ReadSU3(append(data, sig...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return su3
+}
+
+func TestVerifyRSASignedSU3(t *testing.T) {
+	assert := assert.New(t)
+
+	priv, err := rsa.GenerateKey(rand.Reader, 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	su3 := buildRSASignedSU3(t, priv)
+	assert.Nil(su3.Verify(&priv.PublicKey))
+
+	su3.Raw[SU3_HEADER_LEN] ^= 0xff
+	assert.Equal(ERR_SU3_SIGNATURE_INVALID, su3.Verify(&priv.PublicKey))
+
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Equal(ERR_SU3_SIGNER_KEY_MISMATCH, su3.Verify(&ec.PublicKey))
+}
+
+func TestSigningKeyRequiresFingerprint(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := (&ReseedConfig{Url: "https://reseed.example.i2p/"}).SigningKey()
+	assert.Equal(ERR_SU3_FINGERPRINT_MISSING, err)
+	_, err = (&ReseedConfig{SU3Fingerprint: "00", SU3Certificate: "not a certificate"}).SigningKey()
+	assert.Equal(ERR_SU3_CERTIFICATE_INVALID, err)
+}
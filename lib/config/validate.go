@@ -0,0 +1,118 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// every problem found while validating a configuration
+// implements error so callers that only care whether the config is valid can treat it as one
+type ConfigErrors []error
+
+// all the problems, one per line
+func (errs ConfigErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// a problem with the value of one configuration key
+type ConfigError struct {
+	// dotted path of the offending key, i.e. bootstrap.reseed_servers[0].url
+	Key string
+	// what is wrong with it
+	Reason string
+}
+
+func (err *ConfigError) Error() string {
+	return err.Key + ": " + err.Reason
+}
+
+// check the whole router configuration
+// returns nil if it is valid or ConfigErrors listing every problem found
+func (cfg *RouterConfig) Validate() error {
+	var errs ConfigErrors
+	if cfg.NetDb == nil {
+		errs = append(errs, &ConfigError{Key: "netdb", Reason: "missing"})
+	} else {
+		errs = append(errs, cfg.NetDb.validate("netdb")...)
+	}
+	if cfg.Bootstrap == nil {
+		errs = append(errs, &ConfigError{Key: "bootstrap", Reason: "missing"})
+	} else {
+		errs = append(errs, cfg.Bootstrap.validate("bootstrap")...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (cfg *NetDbConfig) validate(prefix string) (errs ConfigErrors) {
+	if cfg.Path == "" {
+		errs = append(errs, &ConfigError{Key: prefix + ".path", Reason: "must not be empty"})
+	} else if info, err := os.Stat(cfg.Path); err == nil && !info.IsDir() {
+		errs = append(errs, &ConfigError{Key: prefix + ".path", Reason: fmt.Sprintf("%s is not a directory", cfg.Path)})
+	}
+	return
+}
+
+func (cfg *BootstrapConfig) validate(prefix string) (errs ConfigErrors) {
+	if cfg.LowPeerThreshold < 0 {
+		errs = append(errs, &ConfigError{
+			Key:    prefix + ".low_peer_threshold",
+			Reason: fmt.Sprintf("must not be negative, got %d", cfg.LowPeerThreshold),
+		})
+	}
+	seen := make(map[string]int)
+	for idx, server := range cfg.ReseedServers {
+		key := fmt.Sprintf("%s.reseed_servers[%d]", prefix, idx)
+		if server == nil {
+			errs = append(errs, &ConfigError{Key: key, Reason: "missing"})
+			continue
+		}
+		u, err := url.Parse(server.Url)
+		if server.Url == "" {
+			errs = append(errs, &ConfigError{Key: key + ".url", Reason: "must not be empty"})
+		} else if err != nil || u.Host == "" {
+			errs = append(errs, &ConfigError{Key: key + ".url", Reason: fmt.Sprintf("%q is not a valid url", server.Url)})
+		} else if u.Scheme != "https" {
+			errs = append(errs, &ConfigError{Key: key + ".url", Reason: fmt.Sprintf("%q must use https", server.Url)})
+		} else if first, ok := seen[server.Url]; ok {
+			errs = append(errs, &ConfigError{Key: key + ".url", Reason: fmt.Sprintf("duplicate of %s.reseed_servers[%d]", prefix, first)})
+		} else {
+			seen[server.Url] = idx
+		}
+		errs = append(errs, server.validateSigningKey(key)...)
+	}
+	return
+}
+
+// check that the su3 signing key is pinned the way SigningKey needs it, so a config that
+// validates does not fail every reseed
+func (server *ReseedConfig) validateSigningKey(key string) (errs ConfigErrors) {
+	if server.SU3Fingerprint == "" {
+		errs = append(errs, &ConfigError{Key: key + ".su3_fingerprint", Reason: "must not be empty"})
+		return
+	}
+	fingerprint := strings.Replace(server.SU3Fingerprint, ":", "", -1)
+	if decoded, err := hex.DecodeString(fingerprint); err != nil || len(decoded) != sha256.Size {
+		errs = append(errs, &ConfigError{
+			Key:    key + ".su3_fingerprint",
+			Reason: fmt.Sprintf("%q is not a hex SHA-256 fingerprint", server.SU3Fingerprint),
+		})
+		return
+	}
+	if server.SU3Certificate == "" {
+		errs = append(errs, &ConfigError{Key: key + ".su3_certificate", Reason: "must not be empty"})
+	} else if _, err := server.SigningKey(); err != nil {
+		errs = append(errs, &ConfigError{Key: key + ".su3_certificate", Reason: err.Error()})
+	}
+	return
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultRouterConfigIsValid(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(DefaultRouterConfig.Validate())
+}
+
+// a self-signed su3 signing certificate and its fingerprint, as a reseed server would pin them
+func buildReseedCertificate(t *testing.T) (fingerprint, certificate string) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test@mail.i2p"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	fingerprint = SU3KeyFingerprint(cert)
+	certificate = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	return
+}
+
+func TestValidateCollectsEveryError(t *testing.T) {
+	assert := assert.New(t)
+
+	fingerprint, certificate := buildReseedCertificate(t)
+	other_fingerprint, _ := buildReseedCertificate(t)
+	cfg := &RouterConfig{
+		NetDb: &NetDbConfig{Path: ""},
+		Bootstrap: &BootstrapConfig{
+			LowPeerThreshold: -1,
+			ReseedServers: []*ReseedConfig{
+				{Url: "https://reseed.example.com/", SU3Fingerprint: fingerprint, SU3Certificate: certificate},
+				{Url: "http://reseed.example.net/", SU3Fingerprint: fingerprint, SU3Certificate: certificate},
+				{Url: "https://reseed.example.com/", SU3Fingerprint: ""},
+				{Url: "", SU3Fingerprint: fingerprint, SU3Certificate: certificate},
+				{Url: "https://reseed.example.org/", SU3Fingerprint: "a@mail.i2p", SU3Certificate: certificate},
+				{Url: "https://reseed2.example.org/", SU3Fingerprint: fingerprint},
+				{Url: "https://reseed3.example.org/", SU3Fingerprint: other_fingerprint, SU3Certificate: certificate},
+			},
+		},
+	}
+	err := cfg.Validate()
+	if assert.NotNil(err) {
+		errs := err.(ConfigErrors)
+		assert.Equal([]string{
+			"netdb.path: must not be empty",
+			"bootstrap.low_peer_threshold: must not be negative, got -1",
+			"bootstrap.reseed_servers[1].url: \"http://reseed.example.net/\" must use https",
+			"bootstrap.reseed_servers[2].url: duplicate of bootstrap.reseed_servers[0]",
+			"bootstrap.reseed_servers[2].su3_fingerprint: must not be empty",
+			"bootstrap.reseed_servers[3].url: must not be empty",
+			"bootstrap.reseed_servers[4].su3_fingerprint: \"a@mail.i2p\" is not a hex SHA-256 fingerprint",
+			"bootstrap.reseed_servers[5].su3_certificate: must not be empty",
+			"bootstrap.reseed_servers[6].su3_certificate: " + ERR_SU3_FINGERPRINT_MISMATCH.Error(),
+		}, errorStrings(errs))
+	}
+}
+
+func TestValidateAcceptsPinnedReseedServer(t *testing.T) {
+	assert := assert.New(t)
+
+	fingerprint, certificate := buildReseedCertificate(t)
+	cfg := &RouterConfig{
+		NetDb: &NetDbConfig{Path: "netDb"},
+		Bootstrap: &BootstrapConfig{
+			ReseedServers: []*ReseedConfig{
+				{Url: "https://reseed.example.com/", SU3Fingerprint: strings.ToUpper(fingerprint), SU3Certificate: certificate},
+			},
+		},
+	}
+	assert.Nil(cfg.Validate())
+}
+
+func TestValidateMissingSections(t *testing.T) {
+	assert := assert.New(t)
+
+	err := (&RouterConfig{}).Validate()
+	if assert.NotNil(err) {
+		assert.Equal("netdb: missing\nbootstrap: missing", err.Error())
+	}
+}
+
+func TestValidateNetDbPathIsFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "go-i2p-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fpath := filepath.Join(dir, "netDb")
+	ioutil.WriteFile(fpath, []byte{}, 0600)
+
+	cfg := &RouterConfig{
+		NetDb:     &NetDbConfig{Path: fpath},
+		Bootstrap: &BootstrapConfig{},
+	}
+	err = cfg.Validate()
+	if assert.NotNil(err) {
+		assert.Equal("netdb.path: "+fpath+" is not a directory", err.Error())
+	}
+}
+
+func errorStrings(errs ConfigErrors) (msgs []string) {
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return
+}
@@ -4,6 +4,7 @@ import (
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"math/big"
 )
 
 type ECDSAVerifier struct {
@@ -13,9 +14,16 @@ type ECDSAVerifier struct {
 }
 
 // verify a signature given the hash
+// the signature is the big-endian r and s values of equal length concatenated together
 func (v *ECDSAVerifier) VerifyHash(h, sig []byte) (err error) {
-	r, s := elliptic.Unmarshal(v.c, sig)
-	if r == nil || s == nil || !ecdsa.Verify(v.k, h, r, s) {
+	sig_len := len(sig)
+	if sig_len == 0 || sig_len%2 != 0 {
+		err = ErrBadSignatureSize
+		return
+	}
+	r := new(big.Int).SetBytes(sig[:sig_len/2])
+	s := new(big.Int).SetBytes(sig[sig_len/2:])
+	if !ecdsa.Verify(v.k, h, r, s) {
 		err = ErrInvalidSignature
 	}
 	return
@@ -24,22 +32,30 @@ func (v *ECDSAVerifier) VerifyHash(h, sig []byte) (err error) {
 // verify a block of data by hashing it and comparing the hash against the signature
 func (v *ECDSAVerifier) Verify(data, sig []byte) (err error) {
 	// sum the data and get the hash
-	h := v.h.New().Sum(data)[len(data):]
+	hasher := v.h.New()
+	hasher.Write(data)
+	h := hasher.Sum(nil)
 	// verify
 	err = v.VerifyHash(h, sig)
 	return
 }
 
+// create a verifier from a public key that is the big-endian x and y coordinates of equal length concatenated together
 func createECVerifier(c elliptic.Curve, h crypto.Hash, k []byte) (ev *ECDSAVerifier, err error) {
-	x, y := elliptic.Unmarshal(c, k[:])
-	if x == nil {
+	x := new(big.Int).SetBytes(k[:len(k)/2])
+	y := new(big.Int).SetBytes(k[len(k)/2:])
+	if !c.IsOnCurve(x, y) {
 		err = ErrInvalidKeyFormat
 	} else {
 		ev = &ECDSAVerifier{
 			c: c,
 			h: h,
 		}
-		ev.k = &ecdsa.PublicKey{c, x, y}
+		ev.k = &ecdsa.PublicKey{
+			Curve: c,
+			X:     x,
+			Y:     y,
+		}
 	}
 	return
 }
@@ -19,6 +19,10 @@ func (k Ed25519PublicKey) NewVerifier() (v Verifier, err error) {
 	return temp, nil
 }
 
+func (k Ed25519PublicKey) Len() int {
+	return len(k)
+}
+
 func (v *Ed25519Verifier) VerifyHash(h, sig []byte) (err error) {
 	if len(sig) != ed25519.SignatureSize {
 		err = ErrBadSignatureSize
@@ -48,6 +52,17 @@ type Ed25519Signer struct {
 	k []byte
 }
 
+func (k Ed25519PrivateKey) NewSigner() (s Signer, err error) {
+	if len(k) != ed25519.PrivateKeySize {
+		err = ErrInvalidKeyFormat
+		return
+	}
+	s = &Ed25519Signer{
+		k: k,
+	}
+	return
+}
+
 func (s *Ed25519Signer) Sign(data []byte) (sig []byte, err error) {
 	if len(s.k) != ed25519.PrivateKeySize {
 		err = errors.New("failed to sign: invalid ed25519 private key size")
@@ -119,15 +119,17 @@ func (elg *ElgamalEncryption) EncryptPadding(data []byte, zeroPadding bool) (enc
 	m := new(big.Int).SetBytes(mbytes)
 	// do encryption
 	b := new(big.Int).Mod(new(big.Int).Mul(elg.b1, m), elg.p).Bytes()
+	a := elg.a.Bytes()
 
+	// a and b are right aligned in 256 bytes each so short values keep their place
 	if zeroPadding {
 		encrypted = make([]byte, 514)
-		copy(encrypted[1:], elg.a.Bytes())
-		copy(encrypted[258:], b)
+		copy(encrypted[257-len(a):], a)
+		copy(encrypted[514-len(b):], b)
 	} else {
 		encrypted = make([]byte, 512)
-		copy(encrypted, elg.a.Bytes())
-		copy(encrypted[256:], b)
+		copy(encrypted[256-len(a):], a)
+		copy(encrypted[512-len(b):], b)
 	}
 	return
 }
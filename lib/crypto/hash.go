@@ -4,4 +4,9 @@ import (
 	"crypto/sha256"
 )
 
+// the hash used for router and destination identity hashes, routing keys and checksums
+// every identity hash is computed through these so the algorithm is chosen in one place
 var SHA256 = sha256.Sum256
+
+// create a streaming hash.Hash computing the same hash as SHA256
+var NewSHA256 = sha256.New
@@ -0,0 +1,19 @@
+package crypto
+
+import (
+	"errors"
+)
+
+var X25519EncryptUnsupported = errors.New("failed to encrypt data, ECIES-X25519 encryption is not implemented")
+
+type X25519PublicKey [32]byte
+
+func (k X25519PublicKey) Len() int {
+	return len(k)
+}
+
+// the ECIES-X25519 layer does not exist yet so there is nothing to encrypt with
+func (k X25519PublicKey) NewEncrypter() (enc Encrypter, err error) {
+	err = X25519EncryptUnsupported
+	return
+}
@@ -0,0 +1,242 @@
+package i2np
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/crypto"
+	log "github.com/sirupsen/logrus"
+)
+
+/*
+Layered encryption of the records in a VariableTunnelBuild, as done by the tunnel creator.
+
+Each hop decrypts its own record with its ElGamal private key, replaces it with its
+BuildResponseRecord and then AES-256-CBC encrypts every record with the reply_key and
+reply_iv from its request.  The creator therefore AES decrypts each request record with
+the reply keys of all hops before it so those hops' encryption cancels out before the
+record reaches its target, and peels the reply keys of every hop from the target onward
+off the reply.
+
+https://geti2p.net/spec/tunnel-creation
+*/
+
+const (
+	BUILD_REQUEST_RECORD_CLEARTEXT_SIZE = 222
+	BUILD_RECORD_SIZE                   = 528
+	BUILD_RECORD_TO_PEER_SIZE           = 16
+	// most records a VariableTunnelBuild can hold, padding to this hides the tunnel length
+	VARIABLE_TUNNEL_BUILD_MAX_RECORDS = 8
+)
+
+// a hop of a tunnel being built, with the keys we need to encrypt its build request
+// record and to decrypt its build response record
+type BuildRecordHop struct {
+	// hash of the hop's RouterIdentity, the first 16 bytes are sent in the clear as toPeer
+	Ident common.Hash
+	// the hop's ElGamal public encryption key
+	EncryptionKey crypto.PublicEncryptionKey
+	// the reply_key and reply_iv in this hop's build request record
+	ReplyKey common.SessionKey
+	ReplyIV  [16]byte
+}
+
+var ERR_BUILD_RECORD_HOP_COUNT_MISMATCH = errors.New("number of build records does not match number of hops")
+var ERR_BUILD_REQUEST_RECORD_CLEARTEXT_SIZE = errors.New("build request record cleartext is not 222 bytes")
+var ERR_VARIABLE_TUNNEL_BUILD_REPLY_NOT_ENOUGH_DATA = errors.New("not enough i2np variable tunnel build reply data")
+var ERR_BUILD_RESPONSE_RECORD_HASH_MISMATCH = errors.New("build response record hash does not match")
+var ERR_BUILD_RECORD_COUNT_INVALID = errors.New("build record count is below the number of hops or above the maximum")
+var ERR_BUILD_RECORD_ORDER_INVALID = errors.New("build record order does not place every hop in a distinct record")
+
+// encrypt the cleartext build request record of each hop so that only that hop can read it,
+// layered so that the records survive the reply encryption of the hops before it
+func EncryptBuildRequestRecords(hops []BuildRecordHop, cleartexts [][]byte) (records []BuildRequestRecordElGamalAES, err error) {
+	if len(hops) != len(cleartexts) {
+		err = ERR_BUILD_RECORD_HOP_COUNT_MISMATCH
+		return
+	}
+	records = make([]BuildRequestRecordElGamalAES, len(hops))
+	for i, hop := range hops {
+		if len(cleartexts[i]) != BUILD_REQUEST_RECORD_CLEARTEXT_SIZE {
+			err = ERR_BUILD_REQUEST_RECORD_CLEARTEXT_SIZE
+			return nil, err
+		}
+		var enc []byte
+		enc, err = encryptBuildRequestRecord(hop, cleartexts[i])
+		if err != nil {
+			log.WithFields(log.Fields{
+				"at":     "i2np.EncryptBuildRequestRecords",
+				"hop":    i,
+				"reason": err.Error(),
+			}).Error("failed to encrypt build request record")
+			return nil, err
+		}
+		copy(records[i][:BUILD_RECORD_TO_PEER_SIZE], hop.Ident[:BUILD_RECORD_TO_PEER_SIZE])
+		copy(records[i][BUILD_RECORD_TO_PEER_SIZE:], enc)
+		for j := i - 1; j >= 0; j-- {
+			err = decryptBuildRecord(hops[j], records[i][:])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return
+}
+
+// create the payload of a VariableTunnelBuild message for these hops
+func CreateVariableTunnelBuild(hops []BuildRecordHop, cleartexts [][]byte) (payload []byte, err error) {
+	order := make([]int, len(hops))
+	for i := range order {
+		order[i] = i
+	}
+	return createVariableTunnelBuild(hops, cleartexts, len(hops), order)
+}
+
+// create the payload of a VariableTunnelBuild message for these hops padded with random
+// decoy records to record_count records, so the record count does not reveal the tunnel length
+// the hop records are placed at random, order gives the record index of each hop which is
+// needed to decrypt the reply
+// decoys are addressed to no hop so every hop ignores them
+func CreatePaddedVariableTunnelBuild(hops []BuildRecordHop, cleartexts [][]byte, record_count int) (payload []byte, order []int, err error) {
+	if record_count < len(hops) || record_count > VARIABLE_TUNNEL_BUILD_MAX_RECORDS {
+		err = ERR_BUILD_RECORD_COUNT_INVALID
+		return
+	}
+	perm, err := randomPermutation(record_count)
+	if err != nil {
+		return
+	}
+	order = perm[:len(hops)]
+	payload, err = createVariableTunnelBuild(hops, cleartexts, record_count, order)
+	if err != nil {
+		order = nil
+	}
+	return
+}
+
+func createVariableTunnelBuild(hops []BuildRecordHop, cleartexts [][]byte, record_count int, order []int) (payload []byte, err error) {
+	records, err := EncryptBuildRequestRecords(hops, cleartexts)
+	if err != nil {
+		return
+	}
+	payload = make([]byte, 1+record_count*BUILD_RECORD_SIZE)
+	payload[0] = byte(record_count)
+	if _, err = rand.Read(payload[1:]); err != nil {
+		return nil, err
+	}
+	for i, record := range records {
+		copy(payload[1+order[i]*BUILD_RECORD_SIZE:], record[:])
+	}
+	return
+}
+
+// peel the reply encryption off the records of a VariableTunnelBuildReply for the tunnel
+// we built with these hops, returning the BuildResponseRecord of every hop
+func DecryptVariableTunnelBuildReply(hops []BuildRecordHop, payload []byte) (records []BuildResponseRecord, err error) {
+	if len(payload) >= 1 && int(common.Integer(payload[:1])) != len(hops) {
+		err = ERR_BUILD_RECORD_HOP_COUNT_MISMATCH
+		return
+	}
+	order := make([]int, len(hops))
+	for i := range order {
+		order[i] = i
+	}
+	return DecryptPaddedVariableTunnelBuildReply(hops, order, payload)
+}
+
+// peel the reply encryption off the records of a VariableTunnelBuildReply for a tunnel built
+// with CreatePaddedVariableTunnelBuild, where order gives the record index of each hop
+// decoy records are ignored
+func DecryptPaddedVariableTunnelBuildReply(hops []BuildRecordHop, order []int, payload []byte) (records []BuildResponseRecord, err error) {
+	if len(payload) < 1 {
+		err = ERR_VARIABLE_TUNNEL_BUILD_REPLY_NOT_ENOUGH_DATA
+		return
+	}
+	count := int(common.Integer(payload[:1]))
+	if len(order) != len(hops) || count < len(hops) {
+		err = ERR_BUILD_RECORD_HOP_COUNT_MISMATCH
+		return
+	}
+	used := make(map[int]bool)
+	for _, index := range order {
+		if index < 0 || index >= count || used[index] {
+			err = ERR_BUILD_RECORD_ORDER_INVALID
+			return
+		}
+		used[index] = true
+	}
+	if len(payload) < 1+count*BUILD_RECORD_SIZE {
+		err = ERR_VARIABLE_TUNNEL_BUILD_REPLY_NOT_ENOUGH_DATA
+		return
+	}
+	records = make([]BuildResponseRecord, len(hops))
+	for i := range hops {
+		data := make([]byte, BUILD_RECORD_SIZE)
+		copy(data, payload[1+order[i]*BUILD_RECORD_SIZE:])
+		for j := len(hops) - 1; j >= i; j-- {
+			err = decryptBuildRecord(hops[j], data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		records[i], err = ReadBuildResponseRecord(data)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"at":  "i2np.DecryptPaddedVariableTunnelBuildReply",
+				"hop": i,
+			}).Warn("build response record failed verification")
+			return nil, err
+		}
+	}
+	return
+}
+
+// return a uniformly random permutation of 0 to n-1
+func randomPermutation(n int) (perm []int, err error) {
+	perm = make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	b := make([]byte, 1)
+	for i := n - 1; i > 0; i-- {
+		// rejection sample so every index is equally likely
+		limit := 256 - 256%(i+1)
+		for {
+			if _, err = rand.Read(b); err != nil {
+				return nil, err
+			}
+			if int(b[0]) < limit {
+				break
+			}
+		}
+		j := int(b[0]) % (i + 1)
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+	return
+}
+
+// ElGamal encrypt a cleartext record to a hop without the zero padding bytes
+func encryptBuildRequestRecord(hop BuildRecordHop, cleartext []byte) (enc []byte, err error) {
+	var encrypter crypto.Encrypter
+	encrypter, err = hop.EncryptionKey.NewEncrypter()
+	if err != nil {
+		return
+	}
+	if elg, ok := encrypter.(*crypto.ElgamalEncryption); ok {
+		enc, err = elg.EncryptPadding(cleartext, false)
+	} else {
+		enc, err = encrypter.Encrypt(cleartext)
+	}
+	return
+}
+
+// AES-256-CBC decrypt a 528 byte record in place with a hop's reply key and iv
+func decryptBuildRecord(hop BuildRecordHop, record []byte) (err error) {
+	block, err := aes.NewCipher(hop.ReplyKey[:])
+	if err == nil {
+		cipher.NewCBCDecrypter(block, hop.ReplyIV[:]).CryptBlocks(record, record)
+	}
+	return
+}
@@ -0,0 +1,181 @@
+package i2np
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"github.com/go-i2p/go-i2p/lib/crypto"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/openpgp/elgamal"
+	"testing"
+)
+
+type testBuildHop struct {
+	BuildRecordHop
+	private_key crypto.ElgPrivateKey
+}
+
+func buildTestHops(t *testing.T, count int) (hops []testBuildHop) {
+	for i := 0; i < count; i++ {
+		priv := new(elgamal.PrivateKey)
+		if err := crypto.ElgamalGenerate(priv, rand.Reader); err != nil {
+			t.Fatal(err)
+		}
+		hop := testBuildHop{}
+		y := priv.Y.Bytes()
+		var pub crypto.ElgPublicKey
+		copy(pub[len(pub)-len(y):], y)
+		x := priv.X.Bytes()
+		copy(hop.private_key[len(hop.private_key)-len(x):], x)
+		hop.EncryptionKey = pub
+		rand.Read(hop.Ident[:])
+		rand.Read(hop.ReplyKey[:])
+		rand.Read(hop.ReplyIV[:])
+		hops = append(hops, hop)
+	}
+	return
+}
+
+// what a participating hop does with a build message: decrypt its own record, replace it
+// with a successful response and reply encrypt every record
+func processBuildAsHop(t *testing.T, hop testBuildHop, payload []byte) (cleartext []byte) {
+	count := int(payload[0])
+	for i := 0; i < count; i++ {
+		record := payload[1+i*BUILD_RECORD_SIZE : 1+(i+1)*BUILD_RECORD_SIZE]
+		if !bytes.Equal(record[:BUILD_RECORD_TO_PEER_SIZE], hop.Ident[:BUILD_RECORD_TO_PEER_SIZE]) {
+			continue
+		}
+		padded := make([]byte, 514)
+		copy(padded[1:257], record[16:272])
+		copy(padded[258:], record[272:])
+		decrypter, _ := hop.private_key.NewDecrypter()
+		var err error
+		cleartext, err = decrypter.Decrypt(padded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := make([]byte, BUILD_RECORD_SIZE)
+		rand.Read(response[32:527])
+		response[527] = 0x00
+		hash := sha256.Sum256(response[32:])
+		copy(response[:32], hash[:])
+		copy(record, response)
+	}
+	block, _ := aes.NewCipher(hop.ReplyKey[:])
+	for i := 0; i < count; i++ {
+		record := payload[1+i*BUILD_RECORD_SIZE : 1+(i+1)*BUILD_RECORD_SIZE]
+		cipher.NewCBCEncrypter(block, hop.ReplyIV[:]).CryptBlocks(record, record)
+	}
+	return
+}
+
+func TestVariableTunnelBuildRoundTripThreeHops(t *testing.T) {
+	assert := assert.New(t)
+
+	test_hops := buildTestHops(t, 3)
+	hops := make([]BuildRecordHop, len(test_hops))
+	cleartexts := make([][]byte, len(test_hops))
+	for i, hop := range test_hops {
+		hops[i] = hop.BuildRecordHop
+		cleartexts[i] = make([]byte, BUILD_REQUEST_RECORD_CLEARTEXT_SIZE)
+		rand.Read(cleartexts[i])
+	}
+
+	payload, err := CreateVariableTunnelBuild(hops, cleartexts)
+	assert.Nil(err)
+	assert.Equal(1+3*BUILD_RECORD_SIZE, len(payload))
+
+	for i, hop := range test_hops {
+		cleartext := processBuildAsHop(t, hop, payload)
+		assert.Equal(cleartexts[i], cleartext, "hop did not decrypt its own build request record")
+	}
+
+	records, err := DecryptVariableTunnelBuildReply(hops, payload)
+	assert.Nil(err)
+	if assert.Equal(3, len(records)) {
+		for _, record := range records {
+			assert.Equal(byte(0x00), record.Reply)
+		}
+	}
+}
+
+func TestDecryptVariableTunnelBuildReplyDetectsTampering(t *testing.T) {
+	assert := assert.New(t)
+
+	hops := []BuildRecordHop{{}}
+	payload := make([]byte, 1+BUILD_RECORD_SIZE)
+	payload[0] = 0x01
+	_, err := DecryptVariableTunnelBuildReply(hops, payload)
+	assert.Equal(ERR_BUILD_RESPONSE_RECORD_HASH_MISMATCH, err)
+}
+
+func TestEncryptBuildRequestRecordsHopCountMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := EncryptBuildRequestRecords([]BuildRecordHop{{}}, nil)
+	assert.Equal(ERR_BUILD_RECORD_HOP_COUNT_MISMATCH, err)
+	_, err = DecryptVariableTunnelBuildReply([]BuildRecordHop{{}}, []byte{0x01})
+	assert.Equal(ERR_VARIABLE_TUNNEL_BUILD_REPLY_NOT_ENOUGH_DATA, err)
+}
+
+func TestPaddedVariableTunnelBuildHidesTunnelLength(t *testing.T) {
+	assert := assert.New(t)
+
+	test_hops := buildTestHops(t, 2)
+	hops := make([]BuildRecordHop, len(test_hops))
+	cleartexts := make([][]byte, len(test_hops))
+	for i, hop := range test_hops {
+		hops[i] = hop.BuildRecordHop
+		cleartexts[i] = make([]byte, BUILD_REQUEST_RECORD_CLEARTEXT_SIZE)
+		rand.Read(cleartexts[i])
+	}
+
+	payload, order, err := CreatePaddedVariableTunnelBuild(hops, cleartexts, VARIABLE_TUNNEL_BUILD_MAX_RECORDS)
+	assert.Nil(err)
+	assert.Equal(byte(VARIABLE_TUNNEL_BUILD_MAX_RECORDS), payload[0])
+	assert.Equal(1+VARIABLE_TUNNEL_BUILD_MAX_RECORDS*BUILD_RECORD_SIZE, len(payload))
+	assert.Equal(2, len(order))
+	assert.NotEqual(order[0], order[1])
+
+	for i := 0; i < VARIABLE_TUNNEL_BUILD_MAX_RECORDS; i++ {
+		if i == order[0] || i == order[1] {
+			continue
+		}
+		to_peer := payload[1+i*BUILD_RECORD_SIZE : 1+i*BUILD_RECORD_SIZE+BUILD_RECORD_TO_PEER_SIZE]
+		for _, hop := range hops {
+			assert.NotEqual(hop.Ident[:BUILD_RECORD_TO_PEER_SIZE], to_peer, "decoy record must not be addressed to a hop")
+		}
+	}
+	for i, hop := range test_hops {
+		cleartext := processBuildAsHop(t, hop, payload)
+		assert.Equal(cleartexts[i], cleartext, "hop did not find its record among the decoys")
+	}
+
+	records, err := DecryptPaddedVariableTunnelBuildReply(hops, order, payload)
+	assert.Nil(err)
+	if assert.Equal(2, len(records)) {
+		for _, record := range records {
+			assert.True(record.Accepted())
+		}
+	}
+}
+
+func TestCreatePaddedVariableTunnelBuildRejectsBadCount(t *testing.T) {
+	assert := assert.New(t)
+
+	hops := []BuildRecordHop{{}, {}}
+	cleartexts := [][]byte{nil, nil}
+	_, _, err := CreatePaddedVariableTunnelBuild(hops, cleartexts, 1)
+	assert.Equal(ERR_BUILD_RECORD_COUNT_INVALID, err)
+	_, _, err = CreatePaddedVariableTunnelBuild(hops, cleartexts, VARIABLE_TUNNEL_BUILD_MAX_RECORDS+1)
+	assert.Equal(ERR_BUILD_RECORD_COUNT_INVALID, err)
+
+	payload := make([]byte, 1+2*BUILD_RECORD_SIZE)
+	payload[0] = 0x02
+	_, err = DecryptPaddedVariableTunnelBuildReply(hops, []int{1, 1}, payload)
+	assert.Equal(ERR_BUILD_RECORD_ORDER_INVALID, err)
+	_, err = DecryptPaddedVariableTunnelBuildReply(hops, []int{0, 2}, payload)
+	assert.Equal(ERR_BUILD_RECORD_ORDER_INVALID, err)
+}
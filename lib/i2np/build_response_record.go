@@ -1,7 +1,11 @@
 package i2np
 
 import (
+	"bytes"
+	"crypto/rand"
+	"errors"
 	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/crypto"
 )
 
 /*
@@ -36,8 +40,27 @@ bytes 32-526 :: random data
 byte  527    :: reply
 
 total length: 528
+
+reply ::
+      1 byte
+      0  :: accept
+      10 :: probabilistic reject, we are approaching a limit
+      20 :: transient overload
+      30 :: bandwidth limit exceeded
+      50 :: critical failure, the router is shutting down or overloaded
+      All nonzero values are rejections, routers send 30 for any reason they
+      do not wish to reveal.
 */
 
+// BuildResponseRecord reply codes
+const (
+	TUNNEL_BUILD_REPLY_ACCEPT               = 0
+	TUNNEL_BUILD_REPLY_PROBABILISTIC_REJECT = 10
+	TUNNEL_BUILD_REPLY_TRANSIENT_OVERLOAD   = 20
+	TUNNEL_BUILD_REPLY_BANDWIDTH            = 30
+	TUNNEL_BUILD_REPLY_CRITICAL             = 50
+)
+
 type BuildResponseRecordELGamalAES [528]byte
 type BuildResponseRecordELGamal [528]byte
 
@@ -46,3 +69,65 @@ type BuildResponseRecord struct {
 	Padding [495]byte
 	Reply   byte
 }
+
+var ERR_BUILD_RESPONSE_RECORD_NOT_ENOUGH_DATA = errors.New("not enough i2np build response record data")
+
+// Create a BuildResponseRecord carrying reply, with random padding and the hash
+// of the padding and reply.
+func NewBuildResponseRecord(reply byte) (record BuildResponseRecord, err error) {
+	_, err = rand.Read(record.Padding[:])
+	if err != nil {
+		return
+	}
+	record.Reply = reply
+	data := record.Bytes()
+	record.Hash = crypto.SHA256(data[32:])
+	return
+}
+
+// Read an unencrypted BuildResponseRecord, checking that its hash matches.
+func ReadBuildResponseRecord(data []byte) (record BuildResponseRecord, err error) {
+	if len(data) < BUILD_RECORD_SIZE {
+		err = ERR_BUILD_RESPONSE_RECORD_NOT_ENOUGH_DATA
+		return
+	}
+	hash := crypto.SHA256(data[32:BUILD_RECORD_SIZE])
+	if !bytes.Equal(hash[:], data[:32]) {
+		err = ERR_BUILD_RESPONSE_RECORD_HASH_MISMATCH
+		return
+	}
+	copy(record.Hash[:], data[:32])
+	copy(record.Padding[:], data[32:527])
+	record.Reply = data[527]
+	return
+}
+
+// Encode this BuildResponseRecord as its 528 unencrypted bytes.
+func (record BuildResponseRecord) Bytes() []byte {
+	data := make([]byte, 0, BUILD_RECORD_SIZE)
+	data = append(data, record.Hash[:]...)
+	data = append(data, record.Padding[:]...)
+	return append(data, record.Reply)
+}
+
+// Return true if the hop that sent this BuildResponseRecord agreed to join the tunnel.
+func (record BuildResponseRecord) Accepted() bool {
+	return record.Reply == TUNNEL_BUILD_REPLY_ACCEPT
+}
+
+// Return a description of why a hop rejected a tunnel, codes between the defined
+// ones are treated as the next more serious defined code.
+func (record BuildResponseRecord) ReplyReason() string {
+	switch {
+	case record.Reply == TUNNEL_BUILD_REPLY_ACCEPT:
+		return "accept"
+	case record.Reply <= TUNNEL_BUILD_REPLY_PROBABILISTIC_REJECT:
+		return "probabilistic reject"
+	case record.Reply <= TUNNEL_BUILD_REPLY_TRANSIENT_OVERLOAD:
+		return "transient overload"
+	case record.Reply <= TUNNEL_BUILD_REPLY_BANDWIDTH:
+		return "bandwidth"
+	default:
+		return "critical"
+	}
+}
@@ -0,0 +1,54 @@
+package i2np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuildResponseRecordReplyCodesRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		reply    byte
+		accepted bool
+		reason   string
+	}{
+		{TUNNEL_BUILD_REPLY_ACCEPT, true, "accept"},
+		{TUNNEL_BUILD_REPLY_PROBABILISTIC_REJECT, false, "probabilistic reject"},
+		{TUNNEL_BUILD_REPLY_TRANSIENT_OVERLOAD, false, "transient overload"},
+		{TUNNEL_BUILD_REPLY_BANDWIDTH, false, "bandwidth"},
+		{TUNNEL_BUILD_REPLY_CRITICAL, false, "critical"},
+	}
+	for _, test := range tests {
+		record, err := NewBuildResponseRecord(test.reply)
+		assert.Nil(err)
+		data := record.Bytes()
+		assert.Equal(BUILD_RECORD_SIZE, len(data))
+		assert.Equal(test.reply, data[527])
+
+		read, err := ReadBuildResponseRecord(data)
+		assert.Nil(err)
+		assert.Equal(record, read)
+		assert.Equal(test.accepted, read.Accepted(), test.reason)
+		assert.Equal(test.reason, read.ReplyReason())
+	}
+}
+
+func TestBuildResponseRecordUndefinedCodeRoundsUp(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("bandwidth", BuildResponseRecord{Reply: 25}.ReplyReason())
+	assert.Equal("critical", BuildResponseRecord{Reply: 255}.ReplyReason())
+}
+
+func TestReadBuildResponseRecordRejectsBadHash(t *testing.T) {
+	assert := assert.New(t)
+
+	record, _ := NewBuildResponseRecord(TUNNEL_BUILD_REPLY_ACCEPT)
+	data := record.Bytes()
+	data[527] = TUNNEL_BUILD_REPLY_CRITICAL
+	_, err := ReadBuildResponseRecord(data)
+	assert.Equal(ERR_BUILD_RESPONSE_RECORD_HASH_MISMATCH, err)
+	_, err = ReadBuildResponseRecord(data[:100])
+	assert.Equal(ERR_BUILD_RESPONSE_RECORD_NOT_ENOUGH_DATA, err)
+}
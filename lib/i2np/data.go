@@ -1,5 +1,12 @@
 package i2np
 
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	log "github.com/sirupsen/logrus"
+)
+
 /*
 I2P I2NP Data
 https://geti2p.net/spec/i2np
@@ -22,3 +29,36 @@ type Data struct {
 	Length int
 	Data   []byte
 }
+
+var ERR_DATA_NOT_ENOUGH_DATA = errors.New("not enough i2np data message data")
+
+func ReadData(data []byte) (Data, error) {
+	i2np_data := Data{}
+	if len(data) < 4 {
+		return i2np_data, ERR_DATA_NOT_ENOUGH_DATA
+	}
+	length := common.Integer(data[0:4])
+	if len(data)-4 < length {
+		log.WithFields(log.Fields{
+			"at":           "i2np.ReadData",
+			"data_len":     len(data) - 4,
+			"required_len": length,
+			"reason":       "payload shorter than length",
+		}).Warn("error parsing i2np data message")
+		return i2np_data, ERR_DATA_NOT_ENOUGH_DATA
+	}
+	i2np_data.Length = length
+	i2np_data.Data = append([]byte{}, data[4:4+length]...)
+
+	log.WithFields(log.Fields{
+		"at":     "i2np.ReadData",
+		"length": length,
+	}).Debug("parsed_i2np_data")
+	return i2np_data, nil
+}
+
+func (data Data) Bytes() []byte {
+	encoded := make([]byte, 4, 4+len(data.Data))
+	binary.BigEndian.PutUint32(encoded, uint32(len(data.Data)))
+	return append(encoded, data.Data...)
+}
@@ -0,0 +1,86 @@
+package i2np
+
+import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/tunnel"
+	log "github.com/sirupsen/logrus"
+	"sync"
+)
+
+var ERR_DATA_NOT_FOR_DESTINATION = errors.New("garlic clove does not carry a data message for a destination")
+var ERR_DATA_UNKNOWN_DESTINATION = errors.New("no local session for the destination of the data message")
+
+// a local destination session that receives the payloads of the Data messages sent to it
+type DataSession interface {
+	ReceiveData(payload []byte)
+}
+
+// routes the payloads of Data messages to the local destination sessions they are for
+type DataDispatcher struct {
+	access   sync.RWMutex
+	sessions map[common.Hash]DataSession
+}
+
+func NewDataDispatcher() *DataDispatcher {
+	return &DataDispatcher{
+		sessions: make(map[common.Hash]DataSession),
+	}
+}
+
+// deliver the payloads of Data messages for the destination with this hash to session
+// replaces any session registered before for the destination
+func (dispatcher *DataDispatcher) Register(destination common.Hash, session DataSession) {
+	dispatcher.access.Lock()
+	dispatcher.sessions[destination] = session
+	dispatcher.access.Unlock()
+}
+
+// stop delivering Data messages for the destination with this hash
+func (dispatcher *DataDispatcher) Unregister(destination common.Hash) {
+	dispatcher.access.Lock()
+	delete(dispatcher.sessions, destination)
+	dispatcher.access.Unlock()
+}
+
+// parse the body of a Data message and hand its payload to the session of destination
+func (dispatcher *DataDispatcher) Deliver(destination common.Hash, data []byte) (err error) {
+	dispatcher.access.RLock()
+	session, ok := dispatcher.sessions[destination]
+	dispatcher.access.RUnlock()
+	if !ok {
+		log.WithFields(log.Fields{
+			"at":          "(DataDispatcher) Deliver",
+			"destination": destination,
+			"reason":      "no session registered",
+		}).Warn("dropping i2np data message")
+		err = ERR_DATA_UNKNOWN_DESTINATION
+		return
+	}
+	i2np_data, err := ReadData(data)
+	if err != nil {
+		return
+	}
+	session.ReceiveData(i2np_data.Data)
+	return
+}
+
+// deliver the Data message of a garlic clove to the local session of the destination
+// its delivery instructions name
+// the clove must use DESTINATION delivery and carry a Data message with an NTCP style header
+func (dispatcher *DataDispatcher) DeliverClove(clove GarlicClove) (err error) {
+	if clove.DeliveryInstructions.Delivery().Mode != tunnel.DELIVERY_DESTINATION {
+		err = ERR_DATA_NOT_FOR_DESTINATION
+		return
+	}
+	header, err := ReadI2NPNTCPHeader(clove.I2NPMessage)
+	if err != nil {
+		return
+	}
+	if header.Type != I2NP_MESSAGE_TYPE_DATA {
+		err = ERR_DATA_NOT_FOR_DESTINATION
+		return
+	}
+	err = dispatcher.Deliver(clove.DeliveryInstructions.Hash, header.Data)
+	return
+}
@@ -0,0 +1,62 @@
+package i2np
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type recordingSession struct {
+	received [][]byte
+}
+
+func (session *recordingSession) ReceiveData(payload []byte) {
+	session.received = append(session.received, payload)
+}
+
+func buildDataClove(flag byte, destination common.Hash, payload []byte) GarlicClove {
+	body := Data{Data: payload}.Bytes()
+	return GarlicClove{
+		DeliveryInstructions: GarlicCloveDeliveryInstructions{Flag: flag, Hash: destination},
+		I2NPMessage:          buildNTCPMessage(body, byte(I2NPChecksum(body))),
+	}
+}
+
+func TestDeliverCloveHandsPayloadToTargetSession(t *testing.T) {
+	assert := assert.New(t)
+
+	target, other := &recordingSession{}, &recordingSession{}
+	dispatcher := NewDataDispatcher()
+	dispatcher.Register(common.Hash{0x01}, target)
+	dispatcher.Register(common.Hash{0x02}, other)
+
+	err := dispatcher.DeliverClove(buildDataClove(0x20, common.Hash{0x01}, []byte("hello i2p")))
+	assert.Nil(err)
+	assert.Equal([][]byte{[]byte("hello i2p")}, target.received)
+	assert.Empty(other.received)
+}
+
+func TestDeliverCloveRejectsUndeliverableCloves(t *testing.T) {
+	assert := assert.New(t)
+
+	session := &recordingSession{}
+	dispatcher := NewDataDispatcher()
+	dispatcher.Register(common.Hash{0x01}, session)
+
+	err := dispatcher.DeliverClove(buildDataClove(0x00, common.Hash{0x01}, []byte{0x01}))
+	assert.Equal(ERR_DATA_NOT_FOR_DESTINATION, err, "LOCAL delivery does not name a destination")
+
+	err = dispatcher.DeliverClove(buildDataClove(0x20, common.Hash{0x03}, []byte{0x01}))
+	assert.Equal(ERR_DATA_UNKNOWN_DESTINATION, err)
+
+	dispatcher.Unregister(common.Hash{0x01})
+	err = dispatcher.DeliverClove(buildDataClove(0x20, common.Hash{0x01}, []byte{0x01}))
+	assert.Equal(ERR_DATA_UNKNOWN_DESTINATION, err)
+
+	clove := buildDataClove(0x20, common.Hash{0x01}, []byte{0x01})
+	clove.I2NPMessage[0] = I2NP_MESSAGE_TYPE_DELIVERY_STATUS
+	dispatcher.Register(common.Hash{0x01}, session)
+	err = dispatcher.DeliverClove(clove)
+	assert.Equal(ERR_DATA_NOT_FOR_DESTINATION, err)
+	assert.Empty(session.received)
+}
@@ -0,0 +1,41 @@
+package i2np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestReadDataWithNoData(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ReadData([]byte{0x00, 0x00})
+	assert.Equal(ERR_DATA_NOT_ENOUGH_DATA, err)
+}
+
+func TestReadDataWithShortPayload(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ReadData([]byte{0x00, 0x00, 0x00, 0x05, 0x01, 0x02})
+	assert.Equal(ERR_DATA_NOT_ENOUGH_DATA, err)
+}
+
+func TestReadDataWithValidData(t *testing.T) {
+	assert := assert.New(t)
+
+	data, err := ReadData([]byte{0x00, 0x00, 0x00, 0x03, 0x01, 0x02, 0x03, 0xff})
+	assert.Nil(err)
+	assert.Equal(3, data.Length)
+	assert.Equal([]byte{0x01, 0x02, 0x03}, data.Data)
+}
+
+func TestDataBytesRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	payload := []byte("hello i2p")
+	encoded := Data{Data: payload}.Bytes()
+	assert.Equal([]byte{0x00, 0x00, 0x00, 0x09}, encoded[:4])
+	data, err := ReadData(encoded)
+	assert.Nil(err)
+	assert.Equal(len(payload), data.Length)
+	assert.Equal(payload, data.Data)
+}
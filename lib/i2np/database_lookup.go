@@ -147,3 +147,8 @@ type DatabaseLookup struct {
 	tags          int
 	ReplyTags     []common.SessionTag
 }
+
+// Return the kind of entry this DatabaseLookup asks for, one of the DATABASE_LOOKUP_TYPE consts.
+func (database_lookup DatabaseLookup) LookupType() int {
+	return int((database_lookup.Flags & 0x0c) >> 2)
+}
@@ -1,7 +1,14 @@
 package i2np
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
 	"github.com/go-i2p/go-i2p/lib/common"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"io/ioutil"
 )
 
 /*
@@ -94,3 +101,124 @@ type DatabaseStore struct {
 	ReplyGateway  common.Hash
 	Data          []byte
 }
+
+// largest RouterInfo we will decompress from a DatabaseStore
+const DATABASE_STORE_MAX_ROUTER_INFO_SIZE = 65535
+
+var ERR_DATABASE_STORE_NOT_ENOUGH_DATA = errors.New("not enough i2np database store data")
+var ERR_DATABASE_STORE_ROUTER_INFO_NOT_COMPRESSED = errors.New("database store router info is not gzip compressed")
+var ERR_DATABASE_STORE_LEASE_SET_COMPRESSED = errors.New("database store lease set is gzip compressed")
+var ERR_DATABASE_STORE_ROUTER_INFO_TOO_LARGE = errors.New("database store router info is too large when decompressed")
+
+// the first two bytes of any gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Read a DatabaseStore, decompressing the RouterInfo it carries if its type is
+// DATABASE_STORE_TYPE_ROUTER_INFO so that Data always holds the uncompressed entry.
+func ReadDatabaseStore(data []byte) (DatabaseStore, error) {
+	database_store := DatabaseStore{}
+	if len(data) < 32+1+4 {
+		return database_store, ERR_DATABASE_STORE_NOT_ENOUGH_DATA
+	}
+	copy(database_store.Key[:], data[0:32])
+	database_store.Type = data[32]
+	copy(database_store.ReplyToken[:], data[33:37])
+	remainder := data[37:]
+	if database_store.HasReplyToken() {
+		if len(remainder) < 4+32 {
+			return database_store, ERR_DATABASE_STORE_NOT_ENOUGH_DATA
+		}
+		copy(database_store.ReplyTunnelID[:], remainder[0:4])
+		copy(database_store.ReplyGateway[:], remainder[4:36])
+		remainder = remainder[36:]
+	}
+
+	if database_store.IsLeaseSet() {
+		if bytes.HasPrefix(remainder, gzipMagic) {
+			log.WithFields(log.Fields{
+				"at":     "i2np.ReadDatabaseStore",
+				"reason": "lease set data starts with gzip header",
+			}).Warn("error parsing i2np database store")
+			return database_store, ERR_DATABASE_STORE_LEASE_SET_COMPRESSED
+		}
+		database_store.Data = append([]byte{}, remainder...)
+		return database_store, nil
+	}
+
+	if len(remainder) < 2 {
+		return database_store, ERR_DATABASE_STORE_NOT_ENOUGH_DATA
+	}
+	length := common.Integer(remainder[0:2])
+	remainder = remainder[2:]
+	if len(remainder) < length {
+		return database_store, ERR_DATABASE_STORE_NOT_ENOUGH_DATA
+	}
+	router_info, err := gunzipRouterInfo(remainder[:length])
+	if err != nil {
+		log.WithFields(log.Fields{
+			"at":     "i2np.ReadDatabaseStore",
+			"reason": err.Error(),
+		}).Warn("error parsing i2np database store")
+		return database_store, err
+	}
+	database_store.Data = router_info
+	return database_store, nil
+}
+
+func gunzipRouterInfo(compressed []byte) ([]byte, error) {
+	if !bytes.HasPrefix(compressed, gzipMagic) {
+		return nil, ERR_DATABASE_STORE_ROUTER_INFO_NOT_COMPRESSED
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	router_info, err := ioutil.ReadAll(io.LimitReader(reader, DATABASE_STORE_MAX_ROUTER_INFO_SIZE+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(router_info) > DATABASE_STORE_MAX_ROUTER_INFO_SIZE {
+		return nil, ERR_DATABASE_STORE_ROUTER_INFO_TOO_LARGE
+	}
+	return router_info, nil
+}
+
+// Return true if this DatabaseStore carries a LeaseSet rather than a RouterInfo.
+func (database_store DatabaseStore) IsLeaseSet() bool {
+	return database_store.Type&0x01 == DATABASE_STORE_TYPE_LEASE_SET
+}
+
+// Return true if this DatabaseStore requests a DeliveryStatus reply.
+func (database_store DatabaseStore) HasReplyToken() bool {
+	return binary.BigEndian.Uint32(database_store.ReplyToken[:]) > 0
+}
+
+// Encode this DatabaseStore, gzip compressing Data if it is a RouterInfo.
+func (database_store DatabaseStore) Bytes() ([]byte, error) {
+	data := append([]byte{}, database_store.Key[:]...)
+	data = append(data, database_store.Type)
+	data = append(data, database_store.ReplyToken[:]...)
+	if database_store.HasReplyToken() {
+		data = append(data, database_store.ReplyTunnelID[:]...)
+		data = append(data, database_store.ReplyGateway[:]...)
+	}
+	if database_store.IsLeaseSet() {
+		return append(data, database_store.Data...), nil
+	}
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(database_store.Data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	if compressed.Len() > 0xffff {
+		return nil, ERR_DATABASE_STORE_ROUTER_INFO_TOO_LARGE
+	}
+	length := make([]byte, 2)
+	binary.BigEndian.PutUint16(length, uint16(compressed.Len()))
+	data = append(data, length...)
+	return append(data, compressed.Bytes()...), nil
+}
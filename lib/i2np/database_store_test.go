@@ -0,0 +1,78 @@
+package i2np
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDatabaseStoreRoundTripCompressedRouterInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := bytes.Repeat([]byte{0x01, 0x02, 0x03}, 200)
+	store := DatabaseStore{Type: DATABASE_STORE_TYPE_ROUTER_INFO, Data: router_info}
+	store.Key[0] = 0xaa
+	data, err := store.Bytes()
+	assert.Nil(err)
+	assert.Equal([]byte{0x1f, 0x8b}, data[32+1+4+2:32+1+4+4], "router info should be gzip compressed")
+	assert.True(len(data) < 32+1+4+2+len(router_info))
+
+	read, err := ReadDatabaseStore(data)
+	assert.Nil(err)
+	assert.Equal(store, read)
+	assert.False(read.IsLeaseSet())
+}
+
+func TestDatabaseStoreRoundTripUncompressedLeaseSet(t *testing.T) {
+	assert := assert.New(t)
+
+	lease_set := bytes.Repeat([]byte{0x04}, 100)
+	store := DatabaseStore{
+		Type:          DATABASE_STORE_TYPE_LEASE_SET,
+		ReplyToken:    [4]byte{0x00, 0x00, 0x00, 0x07},
+		ReplyTunnelID: [4]byte{0x00, 0x00, 0x00, 0x09},
+		Data:          lease_set,
+	}
+	store.ReplyGateway[0] = 0xbb
+	data, err := store.Bytes()
+	assert.Nil(err)
+	assert.Equal(32+1+4+4+32+len(lease_set), len(data))
+
+	read, err := ReadDatabaseStore(data)
+	assert.Nil(err)
+	assert.Equal(store, read)
+	assert.True(read.IsLeaseSet())
+}
+
+func TestDatabaseStoreRejectsUncompressedRouterInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	data := make([]byte, 32+1+4)
+	data = append(data, 0x00, 0x03, 0x01, 0x02, 0x03)
+	_, err := ReadDatabaseStore(data)
+	assert.Equal(ERR_DATABASE_STORE_ROUTER_INFO_NOT_COMPRESSED, err)
+}
+
+func TestDatabaseStoreRejectsCompressedLeaseSet(t *testing.T) {
+	assert := assert.New(t)
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write([]byte{0x04, 0x04})
+	writer.Close()
+	data := make([]byte, 32+1+4)
+	data[32] = DATABASE_STORE_TYPE_LEASE_SET
+	data = append(data, compressed.Bytes()...)
+	_, err := ReadDatabaseStore(data)
+	assert.Equal(ERR_DATABASE_STORE_LEASE_SET_COMPRESSED, err)
+}
+
+func TestDatabaseStoreWithTooLittleData(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ReadDatabaseStore(make([]byte, 36))
+	assert.Equal(ERR_DATABASE_STORE_NOT_ENOUGH_DATA, err)
+	_, err = ReadDatabaseStore(append(make([]byte, 37), 0x00, 0x10, 0x1f))
+	assert.Equal(ERR_DATABASE_STORE_NOT_ENOUGH_DATA, err)
+}
@@ -78,3 +78,38 @@ type GarlicCloveDeliveryInstructions struct {
 	TunnelID   tunnel.TunnelID
 	Delay      int
 }
+
+// read GarlicCloveDeliveryInstructions from the start of data, returning whatever follows them
+func ReadGarlicCloveDeliveryInstructions(data []byte) (instructions GarlicCloveDeliveryInstructions, remainder []byte, err error) {
+	delivery, remainder, err := tunnel.ReadGarlicDelivery(data)
+	if err != nil {
+		return
+	}
+	instructions = GarlicCloveDeliveryInstructions{
+		Flag:     data[0],
+		Hash:     delivery.Hash,
+		TunnelID: delivery.TunnelID,
+		Delay:    delivery.Delay,
+	}
+	return
+}
+
+// the shared tunnel.Delivery these GarlicCloveDeliveryInstructions describe
+// garlic delivery types are numbered the same as the tunnel.DELIVERY_ consts
+func (instructions GarlicCloveDeliveryInstructions) Delivery() tunnel.Delivery {
+	return tunnel.Delivery{
+		Mode:     int((instructions.Flag & 0x60) >> 5),
+		Hash:     instructions.Hash,
+		TunnelID: instructions.TunnelID,
+		HasDelay: instructions.Flag&0x10 == 0x10,
+		Delay:    instructions.Delay,
+	}
+}
+
+// encode these GarlicCloveDeliveryInstructions
+func (instructions GarlicCloveDeliveryInstructions) Bytes() ([]byte, error) {
+	if instructions.Flag&0x80 == 0x80 {
+		return nil, tunnel.ERR_DELIVERY_ENCRYPTED_UNSUPPORTED
+	}
+	return instructions.Delivery().GarlicBytes()
+}
@@ -0,0 +1,36 @@
+package i2np
+
+import (
+	"github.com/go-i2p/go-i2p/lib/tunnel"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGarlicCloveDeliveryInstructionsRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	instructions := GarlicCloveDeliveryInstructions{
+		Flag:     0x70,
+		TunnelID: tunnel.TunnelID(99),
+		Delay:    30,
+	}
+	instructions.Hash[0] = 0x01
+	data, err := instructions.Bytes()
+	assert.Nil(err)
+	assert.Equal(1+32+4+4, len(data))
+
+	read, remainder, err := ReadGarlicCloveDeliveryInstructions(data)
+	assert.Nil(err)
+	assert.Equal(instructions, read)
+	assert.Equal(0, len(remainder))
+	assert.Equal(tunnel.DELIVERY_TUNNEL, read.Delivery().Mode)
+}
+
+func TestGarlicCloveDeliveryInstructionsLocal(t *testing.T) {
+	assert := assert.New(t)
+
+	read, remainder, err := ReadGarlicCloveDeliveryInstructions([]byte{0x00, 0xff})
+	assert.Nil(err)
+	assert.Equal(tunnel.DELIVERY_LOCAL, read.Delivery().Mode)
+	assert.Equal([]byte{0xff}, remainder)
+}
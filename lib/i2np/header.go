@@ -3,6 +3,7 @@ package i2np
 import (
 	"errors"
 	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/crypto"
 	log "github.com/sirupsen/logrus"
 	"time"
 )
@@ -89,6 +90,7 @@ type I2NPSSUHeader struct {
 }
 
 var ERR_I2NP_NOT_ENOUGH_DATA = errors.New("not enough i2np header data")
+var ERR_I2NP_BAD_CHECKSUM = errors.New("i2np checksum does not match message data")
 
 // Read an entire I2NP message and return the parsed header
 // with embedded encrypted data
@@ -137,6 +139,15 @@ func ReadI2NPNTCPHeader(data []byte) (I2NPNTCPHeader, error) {
 		header.Data = message_data
 	}
 
+	if !ValidI2NPChecksum(header.Checksum, header.Data) {
+		log.WithFields(log.Fields{
+			"at":       "i2np.ReadI2NPNTCPHeader",
+			"checksum": header.Checksum,
+			"reason":   "checksum mismatch",
+		}).Warn("dropping corrupted i2np message")
+		return header, ERR_I2NP_BAD_CHECKSUM
+	}
+
 	log.WithFields(log.Fields{
 		"at": "i2np.ReadI2NPNTCPHeader",
 	}).Debug("parsed_i2np_ntcp_header")
@@ -278,3 +289,15 @@ func ReadI2NPNTCPData(data []byte, size int) ([]byte, error) {
 
 	return data[16 : 16+size], nil
 }
+
+// return the checksum of the full I2NP header, the first byte of the SHA-256 of the message data
+// the short header used in tunnels and SSU carries no checksum
+func I2NPChecksum(data []byte) int {
+	sum := crypto.SHA256(data)
+	return int(sum[0])
+}
+
+// return true if checksum matches the message data
+func ValidI2NPChecksum(checksum int, data []byte) bool {
+	return checksum == I2NPChecksum(data)
+}
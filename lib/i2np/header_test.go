@@ -137,3 +137,38 @@ func TestReadI2NPNTCPDataWithValidData(t *testing.T) {
 func TestCrasherRegression123781(t *testing.T) {
 	ReadI2NPNTCPHeader([]byte{0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x00, 0x00, 0x30})
 }
+
+func buildNTCPMessage(payload []byte, checksum byte) []byte {
+	data := []byte{0x14, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x05, 0x26, 0x5c, 0x00}
+	data = append(data, byte(len(payload)>>8), byte(len(payload)), checksum)
+	return append(data, payload...)
+}
+
+func TestReadI2NPNTCPHeaderWithValidChecksum(t *testing.T) {
+	assert := assert.New(t)
+
+	payload := []byte{0x01, 0x02, 0x03}
+	header, err := ReadI2NPNTCPHeader(buildNTCPMessage(payload, 0x03))
+	assert.Nil(err)
+	assert.Equal(I2NP_MESSAGE_TYPE_DATA, header.Type)
+	assert.Equal(0x03, header.Checksum, "checksum is the first byte of the SHA-256 of the data")
+	assert.Equal(payload, header.Data)
+}
+
+func TestReadI2NPNTCPHeaderWithCorruptedChecksum(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ReadI2NPNTCPHeader(buildNTCPMessage([]byte{0x01, 0x02, 0x03}, 0x04))
+	assert.Equal(ERR_I2NP_BAD_CHECKSUM, err)
+
+	_, err = ReadI2NPNTCPHeader(buildNTCPMessage([]byte{0x01, 0x02, 0x04}, 0x03))
+	assert.Equal(ERR_I2NP_BAD_CHECKSUM, err)
+}
+
+func TestReadI2NPSSUHeaderHasNoChecksum(t *testing.T) {
+	assert := assert.New(t)
+
+	header, err := ReadI2NPSSUHeader([]byte{0x14, 0x00, 0x05, 0x26, 0x5c})
+	assert.Nil(err)
+	assert.Equal(I2NP_MESSAGE_TYPE_DATA, header.Type)
+}
@@ -0,0 +1,87 @@
+package i2np
+
+// DatabaseStore type bit 0 values
+const (
+	DATABASE_STORE_TYPE_ROUTER_INFO = 0
+	DATABASE_STORE_TYPE_LEASE_SET   = 1
+)
+
+// DatabaseLookup lookup type flags, bits 3-2 of Flags
+const (
+	DATABASE_LOOKUP_TYPE_NORMAL      = 0
+	DATABASE_LOOKUP_TYPE_LEASE_SET   = 1
+	DATABASE_LOOKUP_TYPE_ROUTER_INFO = 2
+	DATABASE_LOOKUP_TYPE_EXPLORATION = 3
+)
+
+var messageTypeNames = map[int]string{
+	I2NP_MESSAGE_TYPE_DATABASE_STORE:              "DatabaseStore",
+	I2NP_MESSAGE_TYPE_DATABASE_LOOKUP:             "DatabaseLookup",
+	I2NP_MESSAGE_TYPE_DATABASE_SEARCH_REPLY:       "DatabaseSearchReply",
+	I2NP_MESSAGE_TYPE_DELIVERY_STATUS:             "DeliveryStatus",
+	I2NP_MESSAGE_TYPE_GARLIC:                      "Garlic",
+	I2NP_MESSAGE_TYPE_TUNNEL_DATA:                 "TunnelData",
+	I2NP_MESSAGE_TYPE_TUNNEL_GATEWAY:              "TunnelGateway",
+	I2NP_MESSAGE_TYPE_DATA:                        "Data",
+	I2NP_MESSAGE_TYPE_TUNNEL_BUILD:                "TunnelBuild",
+	I2NP_MESSAGE_TYPE_TUNNEL_BUILD_REPLY:          "TunnelBuildReply",
+	I2NP_MESSAGE_TYPE_VARIABLE_TUNNEL_BUILD:       "VariableTunnelBuild",
+	I2NP_MESSAGE_TYPE_VARIABLE_TUNNEL_BUILD_REPLY: "VariableTunnelBuildReply",
+}
+
+// return the spec name of an I2NP message type, or an empty string if it is unknown
+func MessageTypeName(message_type int) string {
+	return messageTypeNames[message_type]
+}
+
+// return the I2NP message type with the given spec name and whether it was found
+func MessageTypeByName(name string) (message_type int, ok bool) {
+	for value, type_name := range messageTypeNames {
+		if type_name == name {
+			return value, true
+		}
+	}
+	return
+}
+
+var databaseStoreTypeNames = map[int]string{
+	DATABASE_STORE_TYPE_ROUTER_INFO: "RouterInfo",
+	DATABASE_STORE_TYPE_LEASE_SET:   "LeaseSet",
+}
+
+var databaseLookupTypeNames = map[int]string{
+	DATABASE_LOOKUP_TYPE_NORMAL:      "Normal",
+	DATABASE_LOOKUP_TYPE_LEASE_SET:   "LeaseSet",
+	DATABASE_LOOKUP_TYPE_ROUTER_INFO: "RouterInfo",
+	DATABASE_LOOKUP_TYPE_EXPLORATION: "Exploration",
+}
+
+// return the name of a DatabaseStore type, or an empty string if it is unknown
+func DatabaseStoreTypeName(store_type int) string {
+	return databaseStoreTypeNames[store_type]
+}
+
+// return the DatabaseStore type with the given name and whether it was found
+func DatabaseStoreTypeByName(name string) (store_type int, ok bool) {
+	for value, type_name := range databaseStoreTypeNames {
+		if type_name == name {
+			return value, true
+		}
+	}
+	return
+}
+
+// return the name of a DatabaseLookup lookup type, or an empty string if it is unknown
+func DatabaseLookupTypeName(lookup_type int) string {
+	return databaseLookupTypeNames[lookup_type]
+}
+
+// return the DatabaseLookup lookup type with the given name and whether it was found
+func DatabaseLookupTypeByName(name string) (lookup_type int, ok bool) {
+	for value, type_name := range databaseLookupTypeNames {
+		if type_name == name {
+			return value, true
+		}
+	}
+	return
+}
@@ -0,0 +1,70 @@
+package i2np
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMessageTypeNamesMatchSpec(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := map[int]string{
+		1:  "DatabaseStore",
+		2:  "DatabaseLookup",
+		3:  "DatabaseSearchReply",
+		10: "DeliveryStatus",
+		11: "Garlic",
+		18: "TunnelData",
+		19: "TunnelGateway",
+		20: "Data",
+		21: "TunnelBuild",
+		22: "TunnelBuildReply",
+		23: "VariableTunnelBuild",
+		24: "VariableTunnelBuildReply",
+	}
+	for value, name := range spec {
+		assert.Equal(name, MessageTypeName(value))
+		message_type, ok := MessageTypeByName(name)
+		assert.True(ok, name)
+		assert.Equal(value, message_type)
+	}
+	assert.Equal("", MessageTypeName(4))
+	_, ok := MessageTypeByName("ShortTunnelBuild")
+	assert.False(ok)
+}
+
+func TestDatabaseStoreTypeNames(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := map[int]string{
+		0: "RouterInfo",
+		1: "LeaseSet",
+	}
+	for value, name := range spec {
+		assert.Equal(name, DatabaseStoreTypeName(value))
+		store_type, ok := DatabaseStoreTypeByName(name)
+		assert.True(ok, name)
+		assert.Equal(value, store_type)
+	}
+	assert.Equal("", DatabaseStoreTypeName(2))
+}
+
+func TestDatabaseLookupTypeNames(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := map[int]string{
+		0: "Normal",
+		1: "LeaseSet",
+		2: "RouterInfo",
+		3: "Exploration",
+	}
+	for value, name := range spec {
+		assert.Equal(name, DatabaseLookupTypeName(value))
+		lookup_type, ok := DatabaseLookupTypeByName(name)
+		assert.True(ok, name)
+		assert.Equal(value, lookup_type)
+	}
+	assert.Equal("", DatabaseLookupTypeName(4))
+	_, ok := DatabaseLookupTypeByName("Any")
+	assert.False(ok)
+}
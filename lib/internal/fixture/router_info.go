@@ -0,0 +1,122 @@
+//
+// Fixtures of I2P structures for the tests of the packages in this module.  Fixtures are
+// built from fixed inputs, so failing to build one is a bug and panics.
+//
+package fixture
+
+import (
+	"crypto/ed25519"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/crypto"
+)
+
+// the Date RouterInfos are published at unless one is given, one day after the epoch
+var DefaultPublished = common.Date{0x00, 0x00, 0x00, 0x00, 0x05, 0x26, 0x5c, 0x00}
+
+//
+// Describes a signed RouterInfo.  The zero value is a RouterInfo with a null certificate,
+// so ElGamal and DSA_SHA1 keys, published at DefaultPublished without any addresses or options.
+//
+type RouterInfo struct {
+	// tells apart the identities and signing keys of otherwise equal RouterInfos
+	Seed byte
+	// KEYCERT_SIGN_DSA_SHA1 or KEYCERT_SIGN_ED25519
+	SigningKeyType int
+	// only written to the Key Certificate, the PublicKey itself is all zero but for Seed
+	CryptoKeyType int
+	// DefaultPublished if zero
+	Published common.Date
+	Addresses []common.RouterAddress
+	Options   map[string]string
+}
+
+//
+// Return the signer of RouterInfos built from this description.
+//
+func (spec RouterInfo) Signer() crypto.Signer {
+	var signer crypto.Signer
+	var err error
+	switch spec.SigningKeyType {
+	case common.KEYCERT_SIGN_DSA_SHA1:
+		signer, err = spec.dsaKey().NewSigner()
+	case common.KEYCERT_SIGN_ED25519:
+		signer, err = spec.ed25519Key().NewSigner()
+	default:
+		panic("fixture: unsupported signing key type " + common.SigningKeyTypeName(spec.SigningKeyType))
+	}
+	must(err)
+	return signer
+}
+
+//
+// Build and sign the described RouterInfo.
+//
+func (spec RouterInfo) Build() common.RouterInfo {
+	public_key := make([]byte, common.PublicKeySize(spec.CryptoKeyType))
+	if len(public_key) > 0 {
+		public_key[0] = spec.Seed
+	}
+	var signing_public_key []byte
+	var cert common.Certificate
+	switch spec.SigningKeyType {
+	case common.KEYCERT_SIGN_DSA_SHA1:
+		dsa_public_key, err := spec.dsaKey().Public()
+		must(err)
+		signing_public_key = dsa_public_key[:]
+	case common.KEYCERT_SIGN_ED25519:
+		signing_public_key = ed25519.PrivateKey(spec.ed25519Key()).Public().(ed25519.PublicKey)
+	}
+	if spec.SigningKeyType == common.KEYCERT_SIGN_DSA_SHA1 && spec.CryptoKeyType == common.KEYCERT_CRYPTO_ELG {
+		cert = common.Certificate{common.CERT_NULL, 0x00, 0x00}
+	} else {
+		cert = common.Certificate(common.NewKeyCertificate(spec.SigningKeyType, spec.CryptoKeyType))
+	}
+	identity, err := common.NewKeysAndCert(public_key, signing_public_key, cert)
+	must(err)
+	published := spec.Published
+	if published == (common.Date{}) {
+		published = DefaultPublished
+	}
+	unsigned, err := common.NewRouterInfo(common.RouterIdentity(identity), published, spec.Addresses, spec.Options)
+	must(err)
+	router_info, err := unsigned.Sign(spec.Signer())
+	must(err)
+	return router_info
+}
+
+//
+// Build a RouterAddress that never expires.
+//
+func Address(cost int, transport_style string, options map[string]string) common.RouterAddress {
+	return ExpiringAddress(cost, common.Date{}, transport_style, options)
+}
+
+//
+// Build a RouterAddress that expires at expiration.
+//
+func ExpiringAddress(cost int, expiration common.Date, transport_style string, options map[string]string) common.RouterAddress {
+	router_address, err := common.NewRouterAddress(cost, expiration, transport_style, options)
+	must(err)
+	return router_address
+}
+
+func (spec RouterInfo) dsaKey() (key crypto.DSAPrivateKey) {
+	for i := range key {
+		key[i] = byte(i+1) ^ spec.Seed
+	}
+	return
+}
+
+func (spec RouterInfo) ed25519Key() crypto.Ed25519PrivateKey {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i+1) ^ spec.Seed
+	}
+	return crypto.Ed25519PrivateKey(ed25519.NewKeyFromSeed(seed))
+}
+
+func must(err error) {
+	if err != nil {
+		panic("fixture: " + err.Error())
+	}
+}
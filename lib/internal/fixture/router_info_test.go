@@ -0,0 +1,34 @@
+package fixture
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRouterInfoBuildsVerifiedRouterInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, spec := range []RouterInfo{
+		{},
+		{Seed: 0x02, Options: map[string]string{"caps": "fR"}},
+		{SigningKeyType: common.KEYCERT_SIGN_ED25519, CryptoKeyType: common.KEYCERT_CRYPTO_X25519},
+		{Addresses: []common.RouterAddress{Address(5, "NTCP2", map[string]string{"host": "127.0.0.1"})}},
+	} {
+		router_info := spec.Build()
+		_, remainder, err := common.ReadRouterInfo(router_info)
+		assert.Nil(err)
+		assert.Empty(remainder)
+		assert.Nil(router_info.Verify())
+		count, _ := router_info.RouterAddressCount()
+		assert.Equal(len(spec.Addresses), count)
+	}
+}
+
+func TestRouterInfoSeedChangesIdentity(t *testing.T) {
+	assert := assert.New(t)
+
+	first, _ := RouterInfo{Seed: 0x01}.Build().IdentHash()
+	second, _ := RouterInfo{Seed: 0x02}.Build().IdentHash()
+	assert.NotEqual(first, second)
+}
@@ -0,0 +1,77 @@
+package naming
+
+import (
+	"encoding/hex"
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/common/base32"
+	"github.com/go-i2p/go-i2p/lib/common/base64"
+	"strings"
+)
+
+// suffix of a base32 address
+const B32_SUFFIX = ".b32.i2p"
+
+var ERR_ADDRESS_UNRECOGNIZED = errors.New("address is not a b32 address, base64 destination or hex hash")
+
+// an i2p address given in any of the forms users pass around
+// Destination is only known if the address was given as a base64 destination
+type Address struct {
+	Hash        common.Hash
+	Destination common.Destination
+}
+
+// parse an address given as a .b32.i2p address, a base64 destination or the hex
+// encoded hash of a destination
+func ParseAddress(str string) (address Address, err error) {
+	str = strings.TrimSpace(str)
+	lower := strings.ToLower(str)
+	if strings.HasSuffix(lower, B32_SUFFIX) {
+		err = address.setHash(base32.DecodeFromString(strings.TrimSuffix(lower, B32_SUFFIX)))
+		return
+	}
+	if len(str) == len(address.Hash)*2 {
+		if err = address.setHash(hex.DecodeString(str)); err == nil {
+			return
+		}
+	}
+	data, err := base64.DecodeFromString(str)
+	if err != nil {
+		err = ERR_ADDRESS_UNRECOGNIZED
+		return
+	}
+	dest, remainder, err := common.ReadDestination(data)
+	if err != nil || len(remainder) > 0 {
+		err = ERR_ADDRESS_UNRECOGNIZED
+		return
+	}
+	address.Destination = dest
+	address.Hash = common.HashData(dest)
+	return
+}
+
+func (address *Address) setHash(data []byte, err error) error {
+	if err != nil || len(data) != len(address.Hash) {
+		return ERR_ADDRESS_UNRECOGNIZED
+	}
+	copy(address.Hash[:], data)
+	return nil
+}
+
+// the hex encoded hash of the destination
+func (address Address) Hex() string {
+	return hex.EncodeToString(address.Hash[:])
+}
+
+// the .b32.i2p address of the destination
+func (address Address) Base32() string {
+	return strings.TrimRight(base32.EncodeToString(address.Hash[:]), "=") + B32_SUFFIX
+}
+
+// the base64 destination, or an empty string if only the hash is known
+func (address Address) Base64() string {
+	if address.Destination == nil {
+		return ""
+	}
+	return address.Destination.Base64()
+}
@@ -0,0 +1,48 @@
+package naming
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestParseAddressConvertsBetweenForms(t *testing.T) {
+	assert := assert.New(t)
+
+	dest := buildDestination(0x07)
+	from_b64, err := ParseAddress(dest.Base64())
+	assert.Nil(err)
+	assert.Equal(dest, from_b64.Destination)
+	assert.Equal(dest.Base32Address(), from_b64.Base32())
+	assert.Equal(dest.Base64(), from_b64.Base64())
+	assert.Equal(64, len(from_b64.Hex()))
+
+	from_b32, err := ParseAddress(from_b64.Base32())
+	assert.Nil(err)
+	assert.Equal(from_b64.Hash, from_b32.Hash)
+	assert.Equal(from_b64.Hex(), from_b32.Hex())
+	assert.Equal("", from_b32.Base64(), "a b32 address does not carry the destination")
+
+	from_hex, err := ParseAddress(from_b64.Hex())
+	assert.Nil(err)
+	assert.Equal(from_b64.Hash, from_hex.Hash)
+	assert.Equal(from_b64.Base32(), from_hex.Base32())
+}
+
+func TestParseAddressToleratesCaseAndWhitespace(t *testing.T) {
+	assert := assert.New(t)
+
+	dest := buildDestination(0x08)
+	address, err := ParseAddress("  " + strings.ToUpper(dest.Base32Address()) + "\n")
+	assert.Nil(err)
+	assert.Equal(dest.Base32Address(), address.Base32())
+}
+
+func TestParseAddressRejectsGarbage(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, input := range []string{"", "example.i2p", "abc.b32.i2p", "not base64!"} {
+		_, err := ParseAddress(input)
+		assert.Equal(ERR_ADDRESS_UNRECOGNIZED, err, input)
+	}
+}
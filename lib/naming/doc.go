@@ -0,0 +1,4 @@
+//
+// resolves human readable i2p host names to destinations
+//
+package naming
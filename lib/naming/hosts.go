@@ -0,0 +1,96 @@
+package naming
+
+import (
+	"bufio"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/common/base64"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"strings"
+	"sync"
+)
+
+// NamingService backed by a local addressbook of name=base64 destination entries
+// as found in a hosts.txt file
+// an entry named *.example.i2p is a delegation by the owner of example.i2p, resolving
+// every subdomain of example.i2p that has no entry of its own
+type HostsNamingService struct {
+	access sync.RWMutex
+	hosts  map[string]common.Destination
+}
+
+// create an empty HostsNamingService
+func NewHostsNamingService() (hosts *HostsNamingService) {
+	hosts = new(HostsNamingService)
+	hosts.hosts = make(map[string]common.Destination)
+	return
+}
+
+// add or replace the Destination for a host name
+func (hosts *HostsNamingService) Add(name string, dest common.Destination) {
+	hosts.access.Lock()
+	hosts.hosts[strings.ToLower(name)] = dest
+	hosts.access.Unlock()
+}
+
+// read hosts.txt formatted entries, one name=base64 destination per line
+// blank lines and lines starting with # are ignored
+// returns how many entries were added, skipping malformed ones
+func (hosts *HostsNamingService) Load(r io.Reader) (count int, err error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), 64*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		// anything after a # in the destination is an extension we do not use
+		b64 := strings.SplitN(parts[1], "#", 2)[0]
+		data, derr := base64.DecodeFromString(b64)
+		if derr != nil {
+			log.WithFields(log.Fields{
+				"at":     "(HostsNamingService) Load",
+				"name":   parts[0],
+				"reason": derr.Error(),
+			}).Warn("skipping malformed hosts entry")
+			continue
+		}
+		dest, _, derr := common.ReadDestination(data)
+		if derr != nil {
+			continue
+		}
+		hosts.Add(parts[0], dest)
+		count++
+	}
+	err = scanner.Err()
+	return
+}
+
+// resolve a host name, falling back to the closest delegated parent domain for subdomains
+// a subdomain never resolves to its parent's own entry, only to a wildcard delegation
+func (hosts *HostsNamingService) Resolve(name string) (common.Destination, error) {
+	name = strings.ToLower(name)
+	hosts.access.RLock()
+	defer hosts.access.RUnlock()
+	if dest, ok := hosts.hosts[name]; ok {
+		return dest, nil
+	}
+	labels := strings.Split(name, ".")
+	// stop before the top level domain, *.i2p is never a valid delegation
+	for i := 1; i < len(labels)-1; i++ {
+		parent := strings.Join(labels[i:], ".")
+		if dest, ok := hosts.hosts["*."+parent]; ok {
+			log.WithFields(log.Fields{
+				"at":     "(HostsNamingService) Resolve",
+				"name":   name,
+				"parent": parent,
+			}).Debug("resolved subdomain by delegation")
+			return dest, nil
+		}
+	}
+	return nil, ERR_NAME_NOT_FOUND
+}
@@ -0,0 +1,45 @@
+package naming
+
+import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	log "github.com/sirupsen/logrus"
+)
+
+var ERR_NAME_NOT_FOUND = errors.New("name not found")
+
+// resolves host names to Destinations
+type NamingService interface {
+	// resolve a host name such as example.i2p to its Destination
+	// returns nil and ERR_NAME_NOT_FOUND if this service does not know the name
+	// returns nil and another error if the lookup itself failed
+	Resolve(name string) (common.Destination, error)
+}
+
+// tries a list of NamingServices in order, using the first one that resolves the name
+type chainNamingService []NamingService
+
+// create a NamingService that asks each of the given NamingServices in order
+// i.e. Chain(addressbook, remote) to only ask a remote naming service for names
+// missing from the local addressbook
+func Chain(services ...NamingService) NamingService {
+	return chainNamingService(services)
+}
+
+func (chain chainNamingService) Resolve(name string) (dest common.Destination, err error) {
+	for idx, service := range chain {
+		dest, err = service.Resolve(name)
+		if err == nil {
+			return
+		}
+		if err != ERR_NAME_NOT_FOUND {
+			log.WithFields(log.Fields{
+				"at":     "(chainNamingService) Resolve",
+				"name":   name,
+				"index":  idx,
+				"reason": err.Error(),
+			}).Warn("naming service failed, trying next")
+		}
+	}
+	return nil, ERR_NAME_NOT_FOUND
+}
@@ -0,0 +1,108 @@
+package naming
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/common/base64"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func buildDestination(seed byte) common.Destination {
+	data := make([]byte, 128+256)
+	data[0] = seed
+	data = append(data, []byte{0x00, 0x00, 0x00}...)
+	return common.Destination(data)
+}
+
+func TestChainUsesSecondServiceOnMiss(t *testing.T) {
+	assert := assert.New(t)
+
+	first := NewHostsNamingService()
+	first.Add("first.i2p", buildDestination(0x01))
+	second := NewHostsNamingService()
+	second.Add("second.i2p", buildDestination(0x02))
+	chain := Chain(first, second)
+
+	dest, err := chain.Resolve("second.i2p")
+	assert.Nil(err)
+	assert.Equal(buildDestination(0x02), dest)
+
+	dest, err = chain.Resolve("first.i2p")
+	assert.Nil(err)
+	assert.Equal(buildDestination(0x01), dest)
+
+	_, err = chain.Resolve("missing.i2p")
+	assert.Equal(ERR_NAME_NOT_FOUND, err)
+}
+
+func TestChainSkipsFailingService(t *testing.T) {
+	assert := assert.New(t)
+
+	hosts := NewHostsNamingService()
+	hosts.Add("example.i2p", buildDestination(0x03))
+	dest, err := Chain(&RemoteNamingService{Server: "names.i2p"}, hosts).Resolve("example.i2p")
+	assert.Nil(err)
+	assert.Equal(buildDestination(0x03), dest)
+}
+
+func TestHostsNamingServiceLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	hosts_txt := strings.Join([]string{
+		"# a comment",
+		"",
+		"example.i2p=" + base64.EncodeToString(buildDestination(0x04)),
+		"Other.i2p=" + base64.EncodeToString(buildDestination(0x05)) + "#!sig=abc",
+		"broken.i2p=not base64!",
+		"noequals",
+	}, "\n")
+	hosts := NewHostsNamingService()
+	count, err := hosts.Load(strings.NewReader(hosts_txt))
+	assert.Nil(err)
+	assert.Equal(2, count)
+
+	dest, err := hosts.Resolve("example.i2p")
+	assert.Nil(err)
+	assert.Equal(buildDestination(0x04), dest)
+	dest, err = hosts.Resolve("other.i2p")
+	assert.Nil(err)
+	assert.Equal(buildDestination(0x05), dest)
+	_, err = hosts.Resolve("broken.i2p")
+	assert.Equal(ERR_NAME_NOT_FOUND, err)
+}
+
+func TestHostsNamingServiceDelegatedSubdomain(t *testing.T) {
+	assert := assert.New(t)
+
+	hosts := NewHostsNamingService()
+	hosts.Add("bar.i2p", buildDestination(0x06))
+	hosts.Add("*.bar.i2p", buildDestination(0x07))
+	hosts.Add("own.bar.i2p", buildDestination(0x08))
+
+	dest, err := hosts.Resolve("foo.bar.i2p")
+	assert.Nil(err)
+	assert.Equal(buildDestination(0x07), dest, "subdomain should resolve to the delegated destination")
+	dest, err = hosts.Resolve("deep.foo.bar.i2p")
+	assert.Nil(err)
+	assert.Equal(buildDestination(0x07), dest)
+	dest, err = hosts.Resolve("own.bar.i2p")
+	assert.Nil(err)
+	assert.Equal(buildDestination(0x08), dest, "an exact entry should win over the delegation")
+	dest, err = hosts.Resolve("bar.i2p")
+	assert.Nil(err)
+	assert.Equal(buildDestination(0x06), dest)
+}
+
+func TestHostsNamingServiceNonDelegatedSubdomainMisses(t *testing.T) {
+	assert := assert.New(t)
+
+	hosts := NewHostsNamingService()
+	hosts.Add("bar.i2p", buildDestination(0x09))
+	hosts.Add("*.i2p", buildDestination(0x0a))
+
+	_, err := hosts.Resolve("foo.bar.i2p")
+	assert.Equal(ERR_NAME_NOT_FOUND, err, "subdomain must not resolve to its parent without a delegation")
+	_, err = hosts.Resolve("other.i2p")
+	assert.Equal(ERR_NAME_NOT_FOUND, err, "a top level wildcard must not be honored")
+}
@@ -0,0 +1,19 @@
+package naming
+
+import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+)
+
+var ERR_REMOTE_NAMING_UNAVAILABLE = errors.New("remote naming lookups are not available")
+
+// NamingService that asks a naming server reachable over i2p
+// TODO: implement the lookup once the router can open client streams
+type RemoteNamingService struct {
+	// host name of the naming server, i.e. names.example.i2p
+	Server string
+}
+
+func (remote *RemoteNamingService) Resolve(name string) (common.Destination, error) {
+	return nil, ERR_REMOTE_NAMING_UNAVAILABLE
+}
@@ -9,6 +9,19 @@ import (
 // wraps a router info and provides serialization
 type Entry struct {
 	ri common.RouterInfo
+	// false if the signature of ri was not checked when it was loaded
+	verified bool
+}
+
+// the RouterInfo of this entry
+func (e *Entry) RouterInfo() common.RouterInfo {
+	return e.ri
+}
+
+// true if the signature of the RouterInfo was checked when it was loaded
+// an unverified entry only comes from a netDb the operator chose to trust
+func (e *Entry) Verified() bool {
+	return e.verified
 }
 
 func (e *Entry) WriteTo(w io.Writer) (err error) {
@@ -0,0 +1,78 @@
+package netdb
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/i2np"
+	"sync"
+	"time"
+)
+
+// how long we remember a processed DatabaseStore payload by default
+const DEFAULT_GOSSIP_FILTER_TTL = 10 * time.Minute
+
+// remembers the content hash of RouterInfos and LeaseSets we recently processed from a
+// DatabaseStore so that the same bytes flooded to us by several floodfills are handled once
+// unlike the netDb this is keyed by the hash of the exact bytes, not by the router or destination hash
+type GossipFilter struct {
+	TTL    time.Duration
+	access sync.Mutex
+	seen   map[common.Hash]time.Time
+}
+
+// create a new empty GossipFilter remembering payloads for ttl
+func NewGossipFilter(ttl time.Duration) (filter *GossipFilter) {
+	filter = &GossipFilter{
+		TTL:  ttl,
+		seen: make(map[common.Hash]time.Time),
+	}
+	return
+}
+
+// hand a received DatabaseStore to process unless identical content was already processed
+// within TTL of now
+// the content is only remembered once process succeeds so a failed store is tried again
+// returns true if process was called along with any error it returned
+func (filter *GossipFilter) Receive(store i2np.DatabaseStore, now time.Time, process func(i2np.DatabaseStore) error) (processed bool, err error) {
+	key := contentKey(store)
+	filter.access.Lock()
+	expires, ok := filter.seen[key]
+	filter.access.Unlock()
+	if ok && now.Before(expires) {
+		return
+	}
+	processed = true
+	err = process(store)
+	if err == nil {
+		filter.access.Lock()
+		filter.seen[key] = now.Add(filter.TTL)
+		filter.access.Unlock()
+	}
+	return
+}
+
+// forget every payload remembered for longer than TTL
+// returns how many were forgotten, for use with a util.Reaper
+func (filter *GossipFilter) Expire(now time.Time) (count int) {
+	filter.access.Lock()
+	for key, expires := range filter.seen {
+		if !now.Before(expires) {
+			delete(filter.seen, key)
+			count++
+		}
+	}
+	filter.access.Unlock()
+	return
+}
+
+// return how many payloads we remember
+func (filter *GossipFilter) Size() (count int) {
+	filter.access.Lock()
+	count = len(filter.seen)
+	filter.access.Unlock()
+	return
+}
+
+// the type is part of the key so a RouterInfo and a LeaseSet with equal bytes stay distinct
+func contentKey(store i2np.DatabaseStore) common.Hash {
+	return common.HashData(append([]byte{store.Type & 0x01}, store.Data...))
+}
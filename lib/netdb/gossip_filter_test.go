@@ -0,0 +1,94 @@
+package netdb
+
+import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/i2np"
+	"github.com/go-i2p/go-i2p/lib/internal/fixture"
+	"github.com/go-i2p/go-i2p/lib/util"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGossipFilterProcessesSameRouterInfoOnce(t *testing.T) {
+	assert := assert.New(t)
+
+	ri := fixture.RouterInfo{}.Build()
+	identity, _ := ri.RouterIdentity()
+	store := i2np.DatabaseStore{
+		Key:  common.HashData(identity),
+		Type: i2np.DATABASE_STORE_TYPE_ROUTER_INFO,
+		Data: ri,
+	}
+	cache := NewVerifyCache()
+	calls := 0
+	process := func(store i2np.DatabaseStore) error {
+		calls++
+		return cache.Verify(common.RouterInfo(store.Data))
+	}
+
+	now := time.Now()
+	filter := NewGossipFilter(DEFAULT_GOSSIP_FILTER_TTL)
+	processed, err := filter.Receive(store, now, process)
+	assert.True(processed)
+	assert.Nil(err)
+	processed, err = filter.Receive(store, now.Add(time.Second), process)
+	assert.False(processed, "identical RouterInfo must not be processed again")
+	assert.Nil(err)
+	assert.Equal(1, calls)
+}
+
+func TestGossipFilterDistinguishesContent(t *testing.T) {
+	assert := assert.New(t)
+
+	ls, hash := buildLeaseSet(0x09)
+	other_ls, _ := buildLeaseSet(0x0a)
+	calls := 0
+	process := func(i2np.DatabaseStore) error {
+		calls++
+		return nil
+	}
+
+	now := time.Now()
+	filter := NewGossipFilter(time.Minute)
+	filter.Receive(i2np.DatabaseStore{Key: hash, Type: i2np.DATABASE_STORE_TYPE_LEASE_SET, Data: ls}, now, process)
+	filter.Receive(i2np.DatabaseStore{Key: hash, Type: i2np.DATABASE_STORE_TYPE_LEASE_SET, Data: other_ls}, now, process)
+	filter.Receive(i2np.DatabaseStore{Key: hash, Type: i2np.DATABASE_STORE_TYPE_ROUTER_INFO, Data: ls}, now, process)
+	assert.Equal(3, calls)
+}
+
+func TestGossipFilterRetriesFailedStore(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	process := func(i2np.DatabaseStore) error {
+		calls++
+		return errors.New("invalid")
+	}
+
+	filter := NewGossipFilter(time.Minute)
+	store := i2np.DatabaseStore{Data: []byte{0x01}}
+	filter.Receive(store, time.Now(), process)
+	filter.Receive(store, time.Now(), process)
+	assert.Equal(2, calls)
+	assert.Equal(0, filter.Size())
+}
+
+func TestGossipFilterForgetsAfterTTL(t *testing.T) {
+	assert := assert.New(t)
+
+	process := func(i2np.DatabaseStore) error { return nil }
+	now := time.Now()
+	filter := NewGossipFilter(time.Minute)
+	store := i2np.DatabaseStore{Data: []byte{0x01}}
+	filter.Receive(store, now, process)
+
+	reaper := util.NewReaper()
+	reaper.Register("gossip", filter)
+	assert.Equal(map[string]int{"gossip": 0}, reaper.Reap(now.Add(time.Second)))
+	assert.Equal(map[string]int{"gossip": 1}, reaper.Reap(now.Add(time.Minute)))
+
+	processed, _ := filter.Receive(store, now.Add(time.Minute), process)
+	assert.True(processed)
+}
@@ -0,0 +1,84 @@
+package netdb
+
+import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+// the looked up RouterInfo is not the tunnel gateway we asked for
+var ERR_GATEWAY_MISMATCH = errors.New("router info does not match the tunnel gateway")
+
+// return the leases of a LeaseSet whose tunnel gateways are in db
+// a gateway missing from db is looked up with resolver, if not nil, waiting at most
+// timeout, and found RouterInfos are stored in db
+// leases whose gateways cannot be found are dropped since we could never reach them
+func UsableLeases(ls common.LeaseSet, db NetworkDatabase, resolver Resolver, timeout time.Duration) (leases []common.Lease, err error) {
+	all, err := ls.Leases()
+	if err != nil {
+		return
+	}
+	for _, lease := range all {
+		gateway := lease.TunnelGateway()
+		if db.GetRouterInfo(gateway) == nil && !lookupGateway(gateway, db, resolver, timeout) {
+			log.WithFields(log.Fields{
+				"at":      "netdb.UsableLeases",
+				"gateway": gateway,
+				"reason":  "tunnel gateway not found",
+			}).Debug("skipping lease")
+			continue
+		}
+		leases = append(leases, lease)
+	}
+	return
+}
+
+// look up a tunnel gateway we do not have, storing it in db if found
+// the found RouterInfo must be the gateway we asked for and its signature must verify
+func lookupGateway(gateway common.Hash, db NetworkDatabase, resolver Resolver, timeout time.Duration) bool {
+	if resolver == nil {
+		return false
+	}
+	chnl := resolver.Lookup(gateway, timeout)
+	if chnl == nil {
+		return false
+	}
+	select {
+	case ri := <-chnl:
+		if ri == nil {
+			return false
+		}
+		if err := checkGateway(gateway, ri); err != nil {
+			log.WithFields(log.Fields{
+				"at":      "netdb.lookupGateway",
+				"gateway": gateway,
+				"reason":  err.Error(),
+			}).Warn("dropping looked up tunnel gateway")
+			return false
+		}
+		db.StoreRouterInfo(ri)
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// check that a looked up RouterInfo is well formed, is the tunnel gateway we asked for and is
+// signed by it
+func checkGateway(gateway common.Hash, ri common.RouterInfo) (err error) {
+	// the identity of a gateway is public, so a matching hash says nothing about the rest
+	if _, _, err = common.ReadRouterInfo(ri); err != nil {
+		return
+	}
+	hash, err := ri.IdentHash()
+	if err != nil {
+		return
+	}
+	if hash != gateway {
+		err = ERR_GATEWAY_MISMATCH
+		return
+	}
+	err = ri.Verify()
+	return
+}
@@ -0,0 +1,125 @@
+package netdb
+
+import (
+	"github.com/go-i2p/go-i2p/lib/bootstrap"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/internal/fixture"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// a NetworkDatabase holding RouterInfos in memory
+type memoryNetDB map[common.Hash]common.RouterInfo
+
+func (db memoryNetDB) GetRouterInfo(hash common.Hash) common.RouterInfo { return db[hash] }
+func (db memoryNetDB) StoreRouterInfo(ri common.RouterInfo) {
+	hash, _ := ri.IdentHash()
+	db[hash] = ri
+}
+func (db memoryNetDB) Reseed(b bootstrap.Bootstrap, minRouters int) error { return nil }
+func (db memoryNetDB) Size() int                                          { return len(db) }
+func (db memoryNetDB) RecalculateSize() error                             { return nil }
+func (db memoryNetDB) Ensure() error                                      { return nil }
+
+// a Resolver that knows a fixed set of RouterInfos
+type memoryResolver map[common.Hash]common.RouterInfo
+
+func (resolver memoryResolver) Lookup(hash common.Hash, timeout time.Duration) chan common.RouterInfo {
+	chnl := make(chan common.RouterInfo, 1)
+	chnl <- resolver[hash]
+	return chnl
+}
+
+func buildLeaseSetWithGateways(gateways ...common.Hash) common.LeaseSet {
+	data := make([]byte, 128+256)
+	data = append(data, []byte{0x00, 0x00, 0x00}...)
+	data = append(data, make([]byte, 256+128)...)
+	data = append(data, byte(len(gateways)))
+	for _, gateway := range gateways {
+		data = append(data, gateway[:]...)
+		data = append(data, make([]byte, 4+8)...)
+	}
+	data = append(data, make([]byte, 40)...)
+	return common.LeaseSet(data)
+}
+
+func TestUsableLeasesSkipsUnknownGateway(t *testing.T) {
+	assert := assert.New(t)
+
+	known := fixture.RouterInfo{Seed: 0x01}.Build()
+	known_hash, _ := known.IdentHash()
+	unknown_hash, _ := fixture.RouterInfo{Seed: 0x02}.Build().IdentHash()
+	db := memoryNetDB{}
+	db.StoreRouterInfo(known)
+
+	leases, err := UsableLeases(buildLeaseSetWithGateways(unknown_hash, known_hash), db, memoryResolver{}, time.Second)
+	assert.Nil(err)
+	assert.Equal(1, len(leases))
+	assert.Equal(known_hash, leases[0].TunnelGateway())
+}
+
+func TestUsableLeasesLooksUpMissingGateway(t *testing.T) {
+	assert := assert.New(t)
+
+	remote := fixture.RouterInfo{}.Build()
+	remote_hash, _ := remote.IdentHash()
+	db := memoryNetDB{}
+	resolver := memoryResolver{remote_hash: remote}
+
+	leases, err := UsableLeases(buildLeaseSetWithGateways(remote_hash), db, resolver, time.Second)
+	assert.Nil(err)
+	assert.Equal(1, len(leases))
+	assert.Equal(remote, db.GetRouterInfo(remote_hash), "found gateway should be stored")
+
+	leases, err = UsableLeases(buildLeaseSetWithGateways(remote_hash), memoryNetDB{}, nil, time.Second)
+	assert.Nil(err)
+	assert.Equal(0, len(leases), "without a resolver missing gateways are dropped")
+}
+
+func TestUsableLeasesRejectsLookedUpGatewayWithOtherIdentity(t *testing.T) {
+	assert := assert.New(t)
+
+	other := fixture.RouterInfo{}.Build()
+	gateway_hash, _ := fixture.RouterInfo{Seed: 0x04}.Build().IdentHash()
+	db := memoryNetDB{}
+	resolver := memoryResolver{gateway_hash: other}
+
+	leases, err := UsableLeases(buildLeaseSetWithGateways(gateway_hash), db, resolver, time.Second)
+	assert.Nil(err)
+	assert.Equal(0, len(leases), "a RouterInfo for another router must not make the lease usable")
+	assert.Equal(0, db.Size(), "a RouterInfo for another router must not be stored")
+}
+
+func TestUsableLeasesRejectsLookedUpGatewayWithBadSignature(t *testing.T) {
+	assert := assert.New(t)
+
+	forged := fixture.RouterInfo{}.Build()
+	forged[len(forged)-1] ^= 0xff
+	forged_hash, _ := forged.IdentHash()
+	db := memoryNetDB{}
+	resolver := memoryResolver{forged_hash: forged}
+
+	leases, err := UsableLeases(buildLeaseSetWithGateways(forged_hash), db, resolver, time.Second)
+	assert.Nil(err)
+	assert.Equal(0, len(leases), "a RouterInfo that does not verify must not make the lease usable")
+	assert.Equal(0, db.Size(), "a RouterInfo that does not verify must not be stored")
+}
+
+func TestUsableLeasesRejectsTruncatedLookedUpGateway(t *testing.T) {
+	assert := assert.New(t)
+
+	signed := fixture.RouterInfo{}.Build()
+	truncated := make(common.RouterInfo, 387)
+	copy(truncated, signed)
+	truncated_hash, _ := truncated.IdentHash()
+	db := memoryNetDB{}
+	resolver := memoryResolver{truncated_hash: truncated}
+
+	assert.NotPanics(func() {
+		leases, err := UsableLeases(buildLeaseSetWithGateways(truncated_hash), db, resolver, time.Second)
+		assert.Nil(err)
+		assert.Equal(0, len(leases), "a truncated RouterInfo must not make the lease usable")
+	})
+	assert.Equal(0, db.Size(), "a truncated RouterInfo must not be stored")
+}
@@ -0,0 +1,121 @@
+package netdb
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"sync"
+	"time"
+)
+
+// a LeaseSet we hold, whether we may hand it out to others and whether it is for a
+// destination we host
+type leaseSetEntry struct {
+	leaseSet  common.LeaseSet
+	published bool
+	local     bool
+}
+
+// storage of LeaseSets keyed by the hash of their Destination
+// keeps the LeaseSets of our own unpublished destinations separate from the ones
+// we serve to lookups so that private destinations are never leaked
+type LeaseSetStore struct {
+	access  sync.RWMutex
+	entries map[common.Hash]leaseSetEntry
+}
+
+// create a new empty LeaseSetStore
+func NewLeaseSetStore() (store *LeaseSetStore) {
+	store = new(LeaseSetStore)
+	store.entries = make(map[common.Hash]leaseSetEntry)
+	return
+}
+
+// store a LeaseSet under the hash of its Destination
+// only published LeaseSets will be returned by Lookup
+// returns error if the LeaseSet's Destination is malformed
+func (store *LeaseSetStore) StoreLeaseSet(ls common.LeaseSet, published bool) (err error) {
+	return store.storeLeaseSet(ls, published, false)
+}
+
+// store the LeaseSet of a destination we host, as StoreLeaseSet
+// only these LeaseSets are saved by SaveLeaseSets
+func (store *LeaseSetStore) StoreLocalLeaseSet(ls common.LeaseSet, published bool) (err error) {
+	return store.storeLeaseSet(ls, published, true)
+}
+
+func (store *LeaseSetStore) storeLeaseSet(ls common.LeaseSet, published, local bool) (err error) {
+	var dest common.Destination
+	dest, err = ls.Destination()
+	if err == nil {
+		store.access.Lock()
+		store.entries[common.HashData(dest)] = leaseSetEntry{
+			leaseSet:  ls,
+			published: published,
+			local:     local,
+		}
+		store.access.Unlock()
+	}
+	return
+}
+
+// obtain a published LeaseSet by its Destination hash to answer a lookup
+// return nil if we do not have it or it is not published
+func (store *LeaseSetStore) Lookup(hash common.Hash) (ls common.LeaseSet) {
+	store.access.RLock()
+	entry, ok := store.entries[hash]
+	store.access.RUnlock()
+	if ok && entry.published {
+		ls = entry.leaseSet
+	}
+	return
+}
+
+// obtain a LeaseSet by its Destination hash regardless of whether it is published
+// for use by our own client destinations only
+// return nil if we do not have it
+func (store *LeaseSetStore) GetLeaseSet(hash common.Hash) (ls common.LeaseSet) {
+	store.access.RLock()
+	entry, ok := store.entries[hash]
+	store.access.RUnlock()
+	if ok {
+		ls = entry.leaseSet
+	}
+	return
+}
+
+// return true if we have a LeaseSet for this Destination hash and it is published
+func (store *LeaseSetStore) IsPublished(hash common.Hash) bool {
+	store.access.RLock()
+	entry, ok := store.entries[hash]
+	store.access.RUnlock()
+	return ok && entry.published
+}
+
+// remove the LeaseSet stored for this Destination hash if we have one
+func (store *LeaseSetStore) RemoveLeaseSet(hash common.Hash) {
+	store.access.Lock()
+	delete(store.entries, hash)
+	store.access.Unlock()
+}
+
+// return how many LeaseSets we have, published or not
+func (store *LeaseSetStore) Size() (count int) {
+	store.access.RLock()
+	count = len(store.entries)
+	store.access.RUnlock()
+	return
+}
+
+// remove every LeaseSet whose leases have all expired before now
+// returns how many LeaseSets were removed, for use with a util.Reaper
+func (store *LeaseSetStore) Expire(now time.Time) (count int) {
+	store.access.Lock()
+	for hash, entry := range store.entries {
+		newest, err := entry.leaseSet.NewestExpiration()
+		if err != nil || newest.Time().Before(now) {
+			delete(store.entries, hash)
+			count++
+		}
+	}
+	store.access.Unlock()
+	return
+}
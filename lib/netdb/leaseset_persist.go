@@ -0,0 +1,141 @@
+package netdb
+
+import (
+	"fmt"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/common/base64"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	leaseSetFilePrefix = "leaseSet-"
+	leaseSetFileSuffix = ".dat"
+)
+
+// get the file a LeaseSet for the Destination with this hash is saved in under dir
+func leaseSetFile(dir string, hash common.Hash) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%s%s", leaseSetFilePrefix, base64.EncodeToString(hash[:]), leaseSetFileSuffix))
+}
+
+// save the published LeaseSets of the destinations we host into dir so that a restart can
+// publish them again, LeaseSets stored for other destinations are never saved
+// each file is written to a temporary file and renamed into place, and LeaseSets saved
+// before that we no longer hold are only removed once every file is written
+// returns error if dir cannot be written
+func (store *LeaseSetStore) SaveLeaseSets(dir string) (err error) {
+	old_files, err := leaseSetFiles(dir)
+	if err != nil {
+		return
+	}
+	saved := make(map[string]bool)
+	store.access.RLock()
+	for hash, entry := range store.entries {
+		if !entry.published || !entry.local {
+			continue
+		}
+		fpath := leaseSetFile(dir, hash)
+		err = writeFileAtomic(fpath, entry.leaseSet)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"at":     "(LeaseSetStore) SaveLeaseSets",
+				"reason": err.Error(),
+			}).Error("failed to save leaseset")
+			break
+		}
+		saved[fpath] = true
+	}
+	store.access.RUnlock()
+	if err != nil {
+		return
+	}
+	for _, fpath := range old_files {
+		if !saved[fpath] {
+			if err = os.Remove(fpath); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// write data to a temporary file beside fpath and rename it over fpath
+func writeFileAtomic(fpath string, data []byte) (err error) {
+	f, err := ioutil.TempFile(filepath.Dir(fpath), "."+filepath.Base(fpath)+".tmp")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0600)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), fpath)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return
+}
+
+// load the LeaseSets saved in dir, store them as published LeaseSets of destinations we host
+// and hand each to republish
+// LeaseSets whose leases have all expired before now, or that are malformed, are deleted
+// instead so that they are regenerated
+// returns how many LeaseSets were restored
+func (store *LeaseSetStore) RestoreLeaseSets(dir string, now time.Time, republish func(common.LeaseSet) error) (count int, err error) {
+	files, err := leaseSetFiles(dir)
+	if err != nil {
+		return
+	}
+	for _, fpath := range files {
+		var data []byte
+		data, err = ioutil.ReadFile(fpath)
+		if err != nil {
+			return
+		}
+		ls := common.LeaseSet(data)
+		newest, lerr := ls.NewestExpiration()
+		if lerr != nil || newest.Time().Before(now) || store.StoreLocalLeaseSet(ls, true) != nil {
+			log.WithFields(log.Fields{
+				"at":   "(LeaseSetStore) RestoreLeaseSets",
+				"file": fpath,
+			}).Debug("discarding expired or malformed leaseset")
+			os.Remove(fpath)
+			continue
+		}
+		count++
+		if republish != nil {
+			if perr := republish(ls); perr != nil {
+				log.WithFields(log.Fields{
+					"at":     "(LeaseSetStore) RestoreLeaseSets",
+					"reason": perr.Error(),
+				}).Warn("failed to republish restored leaseset")
+			}
+		}
+	}
+	return
+}
+
+// list the saved LeaseSet files in dir, none if dir does not exist
+func leaseSetFiles(dir string) (files []string, err error) {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		err = nil
+		return
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if !info.IsDir() && strings.HasPrefix(name, leaseSetFilePrefix) && strings.HasSuffix(name, leaseSetFileSuffix) {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	return
+}
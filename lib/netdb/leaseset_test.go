@@ -0,0 +1,208 @@
+package netdb
+
+import (
+	"encoding/binary"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/i2np"
+	"github.com/go-i2p/go-i2p/lib/util"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func buildLeaseSet(seed byte) (ls common.LeaseSet, hash common.Hash) {
+	dest := make([]byte, 128+256)
+	dest[0] = seed
+	dest = append(dest, []byte{0x00, 0x00, 0x00}...)
+	hash = common.HashData(dest)
+	data := append([]byte{}, dest...)
+	data = append(data, make([]byte, 256+128)...)
+	data = append(data, 0x00)
+	data = append(data, make([]byte, 40)...)
+	ls = common.LeaseSet(data)
+	return
+}
+
+func TestLeaseSetStoreLookupReturnsPublished(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewLeaseSetStore()
+	ls, hash := buildLeaseSet(0x01)
+	assert.Nil(store.StoreLeaseSet(ls, true))
+	assert.Equal(ls, store.Lookup(hash))
+	assert.True(store.IsPublished(hash))
+}
+
+func TestLeaseSetStoreLookupHidesUnpublished(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewLeaseSetStore()
+	ls, hash := buildLeaseSet(0x02)
+	assert.Nil(store.StoreLeaseSet(ls, false))
+	assert.Nil(store.Lookup(hash), "unpublished LeaseSet must not be returned to a lookup")
+	assert.False(store.IsPublished(hash))
+	assert.Equal(ls, store.GetLeaseSet(hash), "unpublished LeaseSet should still be available locally")
+}
+
+func TestLeaseSetStoreKeepsDestinationsSeparate(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewLeaseSetStore()
+	public_ls, public_hash := buildLeaseSet(0x03)
+	private_ls, private_hash := buildLeaseSet(0x04)
+	store.StoreLeaseSet(public_ls, true)
+	store.StoreLeaseSet(private_ls, false)
+	assert.Equal(2, store.Size())
+	assert.Equal(public_ls, store.Lookup(public_hash))
+	assert.Nil(store.Lookup(private_hash))
+
+	store.RemoveLeaseSet(public_hash)
+	assert.Nil(store.GetLeaseSet(public_hash))
+	assert.Equal(1, store.Size())
+}
+
+func TestLeaseSetStoreRejectsMalformedLeaseSet(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewLeaseSetStore()
+	assert.NotNil(store.StoreLeaseSet(common.LeaseSet([]byte{0x00}), true))
+	assert.Equal(0, store.Size())
+}
+
+func TestAnswerLookupServesPublishedLeaseSet(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewLeaseSetStore()
+	ls, hash := buildLeaseSet(0x05)
+	store.StoreLeaseSet(ls, true)
+	database_store, search_reply := store.AnswerLookup(i2np.DatabaseLookup{Key: hash, Flags: 0x04}, common.Hash{})
+	assert.Nil(search_reply)
+	assert.NotNil(database_store)
+	assert.Equal(hash, database_store.Key)
+	assert.True(database_store.IsLeaseSet())
+	assert.Equal([]byte(ls), database_store.Data)
+}
+
+func TestAnswerLookupNeverReturnsUnpublishedLeaseSet(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewLeaseSetStore()
+	ls, hash := buildLeaseSet(0x06)
+	store.StoreLeaseSet(ls, false)
+	us := common.Hash{0x01}
+	for _, flags := range []byte{0x00, 0x04, 0x08, 0x0c} {
+		database_store, search_reply := store.AnswerLookup(i2np.DatabaseLookup{Key: hash, Flags: flags}, us)
+		assert.Nil(database_store, "unpublished LeaseSet must not be returned to a remote lookup")
+		assert.Equal(&i2np.DatabaseSearchReply{Key: hash, From: us}, search_reply)
+	}
+	assert.Equal(ls, store.GetLeaseSet(hash))
+}
+
+func buildLeaseSetExpiring(seed byte, expires time.Time) (ls common.LeaseSet, hash common.Hash) {
+	dest := make([]byte, 128+256)
+	dest[0] = seed
+	dest = append(dest, []byte{0x00, 0x00, 0x00}...)
+	hash = common.HashData(dest)
+	data := append([]byte{}, dest...)
+	data = append(data, make([]byte, 256+128)...)
+	data = append(data, 0x01)
+	data = append(data, make([]byte, 32+4)...)
+	date := make([]byte, 8)
+	binary.BigEndian.PutUint64(date, uint64(expires.UnixNano()/int64(time.Millisecond)))
+	data = append(data, date...)
+	data = append(data, make([]byte, 40)...)
+	ls = common.LeaseSet(data)
+	return
+}
+
+func TestLeaseSetStoreExpireWithReaper(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	store := NewLeaseSetStore()
+	expired_ls, expired_hash := buildLeaseSetExpiring(0x07, now.Add(-time.Minute))
+	fresh_ls, fresh_hash := buildLeaseSetExpiring(0x08, now.Add(10*time.Minute))
+	store.StoreLeaseSet(expired_ls, true)
+	store.StoreLeaseSet(fresh_ls, false)
+
+	reaper := util.NewReaper()
+	reaper.Register("leasesets", store)
+	assert.Equal(map[string]int{"leasesets": 1}, reaper.Reap(now))
+	assert.Nil(store.GetLeaseSet(expired_hash))
+	assert.Equal(fresh_ls, store.GetLeaseSet(fresh_hash))
+}
+
+func TestRestoreLeaseSetsRepublishesValidLeaseSets(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "leasesets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	store := NewLeaseSetStore()
+	valid_ls, valid_hash := buildLeaseSetExpiring(0x0b, now.Add(10*time.Minute))
+	expiring_ls, expiring_hash := buildLeaseSetExpiring(0x0c, now.Add(time.Minute))
+	private_ls, private_hash := buildLeaseSetExpiring(0x0d, now.Add(10*time.Minute))
+	remote_ls, remote_hash := buildLeaseSetExpiring(0x0e, now.Add(10*time.Minute))
+	store.StoreLocalLeaseSet(valid_ls, true)
+	store.StoreLocalLeaseSet(expiring_ls, true)
+	store.StoreLocalLeaseSet(private_ls, false)
+	store.StoreLeaseSet(remote_ls, true)
+	assert.Nil(store.SaveLeaseSets(dir))
+
+	republished := []common.LeaseSet{}
+	restarted := NewLeaseSetStore()
+	count, err := restarted.RestoreLeaseSets(dir, now.Add(5*time.Minute), func(ls common.LeaseSet) error {
+		republished = append(republished, ls)
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal(1, count)
+	assert.Equal([]common.LeaseSet{valid_ls}, republished)
+	assert.Equal(valid_ls, restarted.Lookup(valid_hash))
+	assert.Nil(restarted.GetLeaseSet(expiring_hash), "expired leaseset must be regenerated, not restored")
+	assert.Nil(restarted.GetLeaseSet(private_hash), "unpublished leaseset must not be persisted")
+	assert.Nil(restarted.GetLeaseSet(remote_hash), "leaseset of a destination we do not host must not be persisted")
+
+	files, _ := leaseSetFiles(dir)
+	assert.Equal([]string{leaseSetFile(dir, valid_hash)}, files, "expired leaseset file should be removed")
+}
+
+func TestRestoreLeaseSetsWithoutSavedDirectory(t *testing.T) {
+	assert := assert.New(t)
+
+	count, err := NewLeaseSetStore().RestoreLeaseSets(filepath.Join(os.TempDir(), "no-such-leasesets"), time.Now(), nil)
+	assert.Nil(err)
+	assert.Equal(0, count)
+}
+
+func TestSaveLeaseSetsReplacesStaleFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "leasesets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	store := NewLeaseSetStore()
+	old_ls, old_hash := buildLeaseSetExpiring(0x0f, now.Add(10*time.Minute))
+	store.StoreLocalLeaseSet(old_ls, true)
+	assert.Nil(store.SaveLeaseSets(dir))
+
+	store.RemoveLeaseSet(old_hash)
+	new_ls, new_hash := buildLeaseSetExpiring(0x10, now.Add(10*time.Minute))
+	store.StoreLocalLeaseSet(new_ls, true)
+	assert.Nil(store.SaveLeaseSets(dir))
+
+	infos, _ := ioutil.ReadDir(dir)
+	assert.Len(infos, 1, "stale and temporary files should not be left behind")
+	assert.Equal(filepath.Base(leaseSetFile(dir, new_hash)), infos[0].Name())
+}
@@ -0,0 +1,52 @@
+package netdb
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+)
+
+// loads RouterInfos from the skiplist of a StdNetDB
+type DiskLoader struct {
+	DB StdNetDB
+	// check the signature of every RouterInfo loaded, on by default
+	// turn off only for a trusted local netDb, i.e. one copied from our own router, to
+	// start faster; RouterInfos are still parsed and their entries are marked unverified
+	VerifySignatures bool
+}
+
+// create a DiskLoader for db that verifies signatures
+func NewDiskLoader(db StdNetDB) *DiskLoader {
+	return &DiskLoader{
+		DB:               db,
+		VerifySignatures: true,
+	}
+}
+
+// load the RouterInfo with this hash from the skiplist
+// returns an error if we do not have it, it does not parse or, when VerifySignatures is
+// set, its signature does not verify
+func (loader *DiskLoader) Load(hash common.Hash) (e *Entry, err error) {
+	fname := loader.DB.SkiplistFile(hash)
+	data, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return
+	}
+	ri, _, err := common.ReadRouterInfo(data)
+	if err == nil && loader.VerifySignatures {
+		err = RouterInfoVerifyCache.Verify(ri)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"at":     "(DiskLoader) Load",
+			"file":   fname,
+			"reason": err.Error(),
+		}).Warn("skipping invalid router info")
+		return
+	}
+	e = &Entry{
+		ri:       ri,
+		verified: loader.VerifySignatures,
+	}
+	return
+}
@@ -0,0 +1,68 @@
+package netdb
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/internal/fixture"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// a netDb holding ri, which may not verify, in the skiplist file of hash
+func buildLoaderNetDB(t *testing.T, hash common.Hash, ri common.RouterInfo) StdNetDB {
+	db := StdNetDB(filepath.Join(t.TempDir(), "netDb"))
+	if err := db.Create(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(db.SkiplistFile(hash), ri, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestDiskLoaderVerifiesByDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	ri := fixture.RouterInfo{}.Build()
+	hash, _ := ri.IdentHash()
+	loader := NewDiskLoader(buildLoaderNetDB(t, hash, ri))
+	assert.True(loader.VerifySignatures)
+
+	e, err := loader.Load(hash)
+	if assert.Nil(err) {
+		assert.Equal(ri, e.RouterInfo())
+		assert.True(e.Verified())
+	}
+}
+
+func TestDiskLoaderVerifySignaturesToggle(t *testing.T) {
+	assert := assert.New(t)
+
+	forged := fixture.RouterInfo{}.Build()
+	forged[len(forged)-1] ^= 0xff
+	hash, _ := forged.IdentHash()
+	loader := NewDiskLoader(buildLoaderNetDB(t, hash, forged))
+
+	_, err := loader.Load(hash)
+	assert.NotNil(err, "a RouterInfo that does not verify must not load when verifying")
+
+	loader.VerifySignatures = false
+	e, err := loader.Load(hash)
+	if assert.Nil(err) {
+		assert.Equal(forged, e.RouterInfo())
+		assert.False(e.Verified(), "an entry loaded without verifying must be marked unverified")
+	}
+}
+
+func TestDiskLoaderStillParsesWithoutVerifying(t *testing.T) {
+	assert := assert.New(t)
+
+	ri := fixture.RouterInfo{}.Build()
+	hash, _ := ri.IdentHash()
+	loader := NewDiskLoader(buildLoaderNetDB(t, hash, ri[:len(ri)-1]))
+	loader.VerifySignatures = false
+
+	_, err := loader.Load(hash)
+	assert.NotNil(err, "a truncated RouterInfo must not load even without verifying")
+}
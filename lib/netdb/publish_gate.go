@@ -0,0 +1,69 @@
+package netdb
+
+import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	log "github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// largest clock skew we tolerate before publishing our RouterInfo by default
+const DEFAULT_PUBLISH_SKEW_TOLERANCE = 60 * time.Second
+
+var ERR_CLOCK_NOT_TRUSTED = errors.New("clock not yet trusted")
+
+// refuses to publish our RouterInfo until the clock skew estimate from handshakes is
+// within tolerance, since peers reject a RouterInfo with a badly wrong published date
+type PublishGate struct {
+	Tolerance time.Duration
+	access    sync.RWMutex
+	skew      time.Duration
+	known     bool
+}
+
+// create a PublishGate with no skew estimate that tolerates skew up to tolerance
+func NewPublishGate(tolerance time.Duration) (gate *PublishGate) {
+	gate = &PublishGate{
+		Tolerance: tolerance,
+	}
+	return
+}
+
+// record the latest clock skew estimate, peer time minus our time
+func (gate *PublishGate) ObserveSkew(skew time.Duration) {
+	gate.access.Lock()
+	gate.skew = skew
+	gate.known = true
+	gate.access.Unlock()
+}
+
+// return true if we have a skew estimate and it is within tolerance
+func (gate *PublishGate) Trusted() bool {
+	gate.access.RLock()
+	defer gate.access.RUnlock()
+	skew := gate.skew
+	if skew < 0 {
+		skew = -skew
+	}
+	return gate.known && skew <= gate.Tolerance
+}
+
+// hand our RouterInfo to publish only if the clock is trusted
+// returns ERR_CLOCK_NOT_TRUSTED without calling publish otherwise
+func (gate *PublishGate) Publish(ri common.RouterInfo, publish func(common.RouterInfo) error) (err error) {
+	if !gate.Trusted() {
+		gate.access.RLock()
+		log.WithFields(log.Fields{
+			"at":        "(PublishGate) Publish",
+			"skew":      gate.skew,
+			"known":     gate.known,
+			"tolerance": gate.Tolerance,
+		}).Warn("clock not yet trusted, not publishing RouterInfo")
+		gate.access.RUnlock()
+		err = ERR_CLOCK_NOT_TRUSTED
+		return
+	}
+	err = publish(ri)
+	return
+}
@@ -0,0 +1,33 @@
+package netdb
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/internal/fixture"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPublishGateBlocksWhileSkewIsLarge(t *testing.T) {
+	assert := assert.New(t)
+
+	published := 0
+	publish := func(common.RouterInfo) error {
+		published++
+		return nil
+	}
+	ri := fixture.RouterInfo{}.Build()
+	gate := NewPublishGate(DEFAULT_PUBLISH_SKEW_TOLERANCE)
+
+	assert.Equal(ERR_CLOCK_NOT_TRUSTED, gate.Publish(ri, publish), "no skew estimate yet")
+	gate.ObserveSkew(-5 * time.Minute)
+	assert.Equal(ERR_CLOCK_NOT_TRUSTED, gate.Publish(ri, publish))
+	gate.ObserveSkew(5 * time.Minute)
+	assert.Equal(ERR_CLOCK_NOT_TRUSTED, gate.Publish(ri, publish))
+	assert.Equal(0, published)
+
+	gate.ObserveSkew(-2 * time.Second)
+	assert.True(gate.Trusted())
+	assert.Nil(gate.Publish(ri, publish))
+	assert.Equal(1, published)
+}
@@ -0,0 +1,37 @@
+package netdb
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/i2np"
+	log "github.com/sirupsen/logrus"
+)
+
+// answer a DatabaseLookup from a remote router with the LeaseSets we hold
+// returns a DatabaseStore of the LeaseSet if we have it and it is published, otherwise
+// a DatabaseSearchReply from us for the caller to fill with closer floodfills
+// an unpublished LeaseSet is answered exactly as if we did not have it, so a lookup
+// cannot learn that we host a private destination
+func (store *LeaseSetStore) AnswerLookup(lookup i2np.DatabaseLookup, us common.Hash) (database_store *i2np.DatabaseStore, search_reply *i2np.DatabaseSearchReply) {
+	lookup_type := lookup.LookupType()
+	if lookup_type == i2np.DATABASE_LOOKUP_TYPE_NORMAL || lookup_type == i2np.DATABASE_LOOKUP_TYPE_LEASE_SET {
+		if ls := store.Lookup(lookup.Key); ls != nil {
+			database_store = &i2np.DatabaseStore{
+				Key:  lookup.Key,
+				Type: i2np.DATABASE_STORE_TYPE_LEASE_SET,
+				Data: ls,
+			}
+			return
+		}
+		if store.GetLeaseSet(lookup.Key) != nil {
+			log.WithFields(log.Fields{
+				"at":     "(LeaseSetStore) AnswerLookup",
+				"reason": "lease set is not published",
+			}).Debug("not serving lease set to remote lookup")
+		}
+	}
+	search_reply = &i2np.DatabaseSearchReply{
+		Key:  lookup.Key,
+		From: us,
+	}
+	return
+}
@@ -1,7 +1,6 @@
 package netdb
 
 import (
-	"bytes"
 	"fmt"
 	"github.com/go-i2p/go-i2p/lib/bootstrap"
 	"github.com/go-i2p/go-i2p/lib/common"
@@ -19,17 +18,15 @@ import (
 // standard network database implementation using local filesystem skiplist
 type StdNetDB string
 
+// load the RouterInfo with this hash from the skiplist
+// returns nil if we do not have it or its signature does not verify
 func (db StdNetDB) GetRouterInfo(hash common.Hash) (chnl chan common.RouterInfo) {
-	fname := db.SkiplistFile(hash)
-	f, err := os.Open(fname)
+	e, err := NewDiskLoader(db).Load(hash)
 	if err != nil {
 		return nil
 	}
-	buff := new(bytes.Buffer)
-	_, err = io.Copy(buff, f)
-	f.Close()
-	chnl = make(chan common.RouterInfo)
-	chnl <- common.RouterInfo(buff.Bytes())
+	chnl = make(chan common.RouterInfo, 1)
+	chnl <- e.RouterInfo()
 	return
 }
 
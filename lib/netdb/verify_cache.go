@@ -0,0 +1,94 @@
+package netdb
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/util"
+	"sync"
+	"time"
+)
+
+// how long a verified RouterInfo is remembered by default
+const DEFAULT_VERIFY_CACHE_TTL = time.Hour
+
+// how many verified RouterInfos are remembered by default
+const DEFAULT_VERIFY_CACHE_SIZE = 8192
+
+// the VerifyCache consulted when StdNetDB loads a RouterInfo
+var RouterInfoVerifyCache = NewVerifyCache()
+
+// remembers RouterInfos whose signature already verified, keyed by the hash of their exact bytes
+// so that loading an unchanged RouterInfo again skips the signature check
+// any change to the bytes gives a different key and is verified again
+// at most MaxEntries are remembered, once full new RouterInfos are still verified but not
+// remembered until Expire makes room
+type VerifyCache struct {
+	// how long a verified RouterInfo is remembered
+	TTL time.Duration
+	// how many verified RouterInfos are remembered
+	MaxEntries int
+	// the time entries are remembered at, the system clock by default
+	Clock    util.Clock
+	access   sync.RWMutex
+	verified map[common.Hash]time.Time
+}
+
+// create a new empty VerifyCache
+func NewVerifyCache() (cache *VerifyCache) {
+	cache = new(VerifyCache)
+	cache.TTL = DEFAULT_VERIFY_CACHE_TTL
+	cache.MaxEntries = DEFAULT_VERIFY_CACHE_SIZE
+	cache.Clock = util.SystemClock
+	cache.verified = make(map[common.Hash]time.Time)
+	return
+}
+
+// verify the signature of a RouterInfo unless these exact bytes already verified
+// returns nil if the RouterInfo is valid
+func (cache *VerifyCache) Verify(ri common.RouterInfo) (err error) {
+	key := common.HashData(ri)
+	now := cache.Clock.Now()
+	cache.access.RLock()
+	verified, ok := cache.verified[key]
+	cache.access.RUnlock()
+	if ok && now.Sub(verified) < cache.TTL {
+		return
+	}
+	err = ri.Verify()
+	if err == nil {
+		cache.access.Lock()
+		if _, ok := cache.verified[key]; ok || len(cache.verified) < cache.MaxEntries {
+			cache.verified[key] = now
+		}
+		cache.access.Unlock()
+	}
+	return
+}
+
+// forget that the RouterInfo with these bytes verified
+func (cache *VerifyCache) Invalidate(ri common.RouterInfo) {
+	cache.access.Lock()
+	delete(cache.verified, common.HashData(ri))
+	cache.access.Unlock()
+}
+
+// return how many verified RouterInfos we remember
+func (cache *VerifyCache) Size() (count int) {
+	cache.access.RLock()
+	count = len(cache.verified)
+	cache.access.RUnlock()
+	return
+}
+
+// forget every RouterInfo verified more than TTL before now
+// returns how many were forgotten, for use with a util.Reaper
+func (cache *VerifyCache) Expire(now time.Time) (count int) {
+	cache.access.Lock()
+	for key, verified := range cache.verified {
+		if now.Sub(verified) >= cache.TTL {
+			delete(cache.verified, key)
+			count++
+		}
+	}
+	cache.access.Unlock()
+	return
+}
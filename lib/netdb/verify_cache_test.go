@@ -0,0 +1,113 @@
+package netdb
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/internal/fixture"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestVerifyCacheRemembersValidRouterInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := NewVerifyCache()
+	ri := fixture.RouterInfo{}.Build()
+	assert.Nil(cache.Verify(ri))
+	assert.Equal(1, cache.Size())
+	assert.Nil(cache.Verify(ri))
+	assert.Equal(1, cache.Size())
+
+	cache.Invalidate(ri)
+	assert.Equal(0, cache.Size())
+}
+
+func TestVerifyCacheRechecksChangedBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := NewVerifyCache()
+	ri := fixture.RouterInfo{}.Build()
+	assert.Nil(cache.Verify(ri))
+
+	tampered := append(common.RouterInfo{}, ri...)
+	tampered[400] ^= 0xff
+	assert.NotNil(cache.Verify(tampered), "changed RouterInfo must be verified again")
+	assert.Equal(1, cache.Size())
+}
+
+func BenchmarkVerifyRouterInfoCold(b *testing.B) {
+	ri := fixture.RouterInfo{}.Build()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		NewVerifyCache().Verify(ri)
+	}
+}
+
+func BenchmarkVerifyRouterInfoWarm(b *testing.B) {
+	ri := fixture.RouterInfo{}.Build()
+	cache := NewVerifyCache()
+	cache.Verify(ri)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		cache.Verify(ri)
+	}
+}
+
+func TestVerifyCacheStopsGrowingWhenFull(t *testing.T) {
+	assert := assert.New(t)
+
+	cache := NewVerifyCache()
+	cache.MaxEntries = 0
+	ri := fixture.RouterInfo{}.Build()
+	assert.Nil(cache.Verify(ri), "a full cache must still verify")
+	assert.Equal(0, cache.Size())
+}
+
+// a util.Clock stopped at one time
+type fixedClock time.Time
+
+func (clock fixedClock) Now() time.Time {
+	return time.Time(clock)
+}
+
+func TestVerifyCacheExpiresOldEntries(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Unix(1000, 0)
+	cache := NewVerifyCache()
+	cache.Clock = fixedClock(start)
+	ri := fixture.RouterInfo{}.Build()
+	assert.Nil(cache.Verify(ri))
+
+	assert.Equal(0, cache.Expire(start.Add(cache.TTL-time.Second)))
+	assert.Equal(1, cache.Size())
+	assert.Equal(1, cache.Expire(start.Add(cache.TTL)))
+	assert.Equal(0, cache.Size())
+}
+
+func TestStdNetDBGetRouterInfoVerifiesThroughCache(t *testing.T) {
+	assert := assert.New(t)
+
+	db := StdNetDB(filepath.Join(t.TempDir(), "netDb"))
+	assert.Nil(db.Create())
+	ri := fixture.RouterInfo{}.Build()
+	hash, err := ri.IdentHash()
+	assert.Nil(err)
+	assert.Nil(os.WriteFile(db.SkiplistFile(hash), ri, 0600))
+
+	RouterInfoVerifyCache.Invalidate(ri)
+	chnl := db.GetRouterInfo(hash)
+	if assert.NotNil(chnl) {
+		assert.Equal(ri, <-chnl)
+	}
+	before := RouterInfoVerifyCache.Size()
+	RouterInfoVerifyCache.Invalidate(ri)
+	assert.Equal(before-1, RouterInfoVerifyCache.Size(), "load must go through RouterInfoVerifyCache")
+
+	tampered := append(common.RouterInfo{}, ri...)
+	tampered[400] ^= 0xff
+	assert.Nil(os.WriteFile(db.SkiplistFile(hash), tampered, 0600))
+	assert.Nil(db.GetRouterInfo(hash), "RouterInfo that does not verify must not be loaded")
+}
@@ -0,0 +1,4 @@
+//
+// installs i2p plugins from signed su3 files
+//
+package plugin
@@ -0,0 +1,190 @@
+package plugin
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/config"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// largest file we will extract from a plugin
+const PLUGIN_MAX_FILE_SIZE = 64 * 1024 * 1024
+
+// largest total size of the files we will extract from a plugin
+const PLUGIN_MAX_SIZE = 256 * 1024 * 1024
+
+var ERR_PLUGIN_BAD_CONTENT = errors.New("plugin su3 does not contain a zipped plugin")
+var ERR_PLUGIN_CONFIG_NOT_FOUND = errors.New("plugin does not contain a plugin.config")
+var ERR_PLUGIN_SIGNER_MISMATCH = errors.New("plugin.config signer does not match the su3 signer")
+var ERR_PLUGIN_BAD_PATH = errors.New("plugin contains a file outside its directory")
+var ERR_PLUGIN_TOO_LARGE = errors.New("plugin is larger than allowed")
+var ERR_PLUGIN_ALREADY_INSTALLED = errors.New("plugin is already installed")
+
+// a plugin su3 whose signature verified, ready to be extracted
+type Plugin struct {
+	Config  Config
+	archive *zip.Reader
+}
+
+// read a plugin su3 signed with public_key and parse its plugin.config
+// the su3 must hold a zipped plugin and be signed by the signer its plugin.config names
+func Open(data []byte, public_key crypto.PublicKey) (plugin *Plugin, err error) {
+	su3, err := config.ReadSU3(data)
+	if err != nil {
+		return
+	}
+	if err = su3.Verify(public_key); err != nil {
+		return
+	}
+	if su3.CheckContent(config.SU3_CONTENT_TYPE_PLUGIN_UPDATE, config.SU3_FILE_TYPE_ZIP) != nil {
+		err = ERR_PLUGIN_BAD_CONTENT
+		return
+	}
+	archive, err := zip.NewReader(bytes.NewReader(su3.Content), int64(len(su3.Content)))
+	if err != nil {
+		return
+	}
+	var cfg Config
+	for _, file := range archive.File {
+		if file.Name != PLUGIN_CONFIG_FILENAME {
+			continue
+		}
+		var data []byte
+		if data, err = readFile(file); err != nil {
+			return
+		}
+		if cfg, err = ParseConfig(data); err != nil {
+			return
+		}
+	}
+	if cfg == nil {
+		err = ERR_PLUGIN_CONFIG_NOT_FOUND
+		return
+	}
+	if cfg.Signer() != su3.SignerID {
+		log.WithFields(log.Fields{
+			"at":         "plugin.Open",
+			"plugin":     cfg.Name(),
+			"signer":     cfg.Signer(),
+			"su3_signer": su3.SignerID,
+		}).Warn(ERR_PLUGIN_SIGNER_MISMATCH)
+		err = ERR_PLUGIN_SIGNER_MISMATCH
+		return
+	}
+	plugin = &Plugin{
+		Config:  cfg,
+		archive: archive,
+	}
+	return
+}
+
+// verify a plugin su3 signed with public_key and extract it into a directory named after
+// the plugin inside dir, returning its plugin.config
+// nothing is left in dir if the plugin cannot be extracted completely
+func Install(data []byte, public_key crypto.PublicKey, dir string) (cfg Config, err error) {
+	plugin, err := Open(data, public_key)
+	if err != nil {
+		return
+	}
+	if err = plugin.Extract(dir); err != nil {
+		return
+	}
+	cfg = plugin.Config
+	return
+}
+
+// extract the files of this plugin into a directory named after it inside dir
+// the files are extracted into a temporary directory that is renamed into place once
+// every file is written
+func (plugin *Plugin) Extract(dir string) (err error) {
+	target := filepath.Join(dir, plugin.Config.Name())
+	if _, err = os.Stat(target); err == nil {
+		err = ERR_PLUGIN_ALREADY_INSTALLED
+		return
+	}
+	tmp, err := ioutil.TempDir(dir, "."+plugin.Config.Name()+"-")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(tmp)
+	total := 0
+	for _, file := range plugin.archive.File {
+		var size int
+		if size, err = extractFile(file, tmp); err != nil {
+			log.WithFields(log.Fields{
+				"at":     "(Plugin) Extract",
+				"plugin": plugin.Config.Name(),
+				"file":   file.Name,
+				"reason": err.Error(),
+			}).Error("failed to extract plugin")
+			return
+		}
+		total += size
+		if total > PLUGIN_MAX_SIZE {
+			err = ERR_PLUGIN_TOO_LARGE
+			return
+		}
+	}
+	err = os.Rename(tmp, target)
+	return
+}
+
+// extract one file of a plugin into dir, returning how many bytes were written
+func extractFile(file *zip.File, dir string) (size int, err error) {
+	if path.IsAbs(file.Name) || strings.Contains(file.Name, "\\") {
+		err = ERR_PLUGIN_BAD_PATH
+		return
+	}
+	name := path.Clean(file.Name)
+	if name == ".." || strings.HasPrefix(name, "../") {
+		err = ERR_PLUGIN_BAD_PATH
+		return
+	}
+	fpath := filepath.Join(dir, filepath.FromSlash(name))
+	if file.FileInfo().IsDir() {
+		err = os.MkdirAll(fpath, 0700)
+		return
+	}
+	if !file.Mode().IsRegular() {
+		err = ERR_PLUGIN_BAD_PATH
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(fpath), 0700); err != nil {
+		return
+	}
+	data, err := readFile(file)
+	if err != nil {
+		return
+	}
+	mode := os.FileMode(0600)
+	if file.Mode()&0100 != 0 {
+		mode = 0700
+	}
+	err = ioutil.WriteFile(fpath, data, mode)
+	size = len(data)
+	return
+}
+
+// read a file of a plugin, returning ERR_PLUGIN_TOO_LARGE if it holds more than
+// PLUGIN_MAX_FILE_SIZE bytes
+func readFile(file *zip.File) (data []byte, err error) {
+	rc, err := file.Open()
+	if err != nil {
+		return
+	}
+	defer rc.Close()
+	data, err = ioutil.ReadAll(io.LimitReader(rc, PLUGIN_MAX_FILE_SIZE+1))
+	if err == nil && len(data) > PLUGIN_MAX_FILE_SIZE {
+		data = nil
+		err = ERR_PLUGIN_TOO_LARGE
+	}
+	return
+}
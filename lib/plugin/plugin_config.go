@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+)
+
+// name of the metadata file at the root of every plugin
+const PLUGIN_CONFIG_FILENAME = "plugin.config"
+
+var ERR_PLUGIN_CONFIG_MISSING_KEY = errors.New("plugin.config is missing a required key")
+var ERR_PLUGIN_CONFIG_BAD_NAME = errors.New("plugin name is not a valid directory name")
+
+// keys every plugin.config must set
+var requiredConfigKeys = []string{"name", "signer", "version"}
+
+// the properties of a plugin.config, i.e. name, signer, version, description
+type Config map[string]string
+
+// parse a plugin.config, one key=value per line
+// blank lines and lines starting with # are ignored, as are lines without an =
+func ParseConfig(data []byte) (cfg Config, err error) {
+	cfg = make(Config)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		idx := strings.Index(line, "=")
+		if idx < 0 {
+			continue
+		}
+		cfg[strings.TrimSpace(line[:idx])] = strings.TrimSpace(line[idx+1:])
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	for _, key := range requiredConfigKeys {
+		if cfg[key] == "" {
+			return nil, ERR_PLUGIN_CONFIG_MISSING_KEY
+		}
+	}
+	name := cfg.Name()
+	if name == "." || name == ".." || strings.ContainsAny(name, "/\\") {
+		return nil, ERR_PLUGIN_CONFIG_BAD_NAME
+	}
+	return
+}
+
+// the name of the plugin, also the directory it is installed in
+func (cfg Config) Name() string {
+	return cfg["name"]
+}
+
+// the signer id of the plugin, must match the signer id of its su3
+func (cfg Config) Signer() string {
+	return cfg["signer"]
+}
+
+// the version of the plugin
+func (cfg Config) Version() string {
+	return cfg["version"]
+}
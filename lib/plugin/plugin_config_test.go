@@ -0,0 +1,35 @@
+package plugin
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg, err := ParseConfig([]byte("# comment\n\nname = example\nsigner=dev@mail.i2p\nversion=1.0\nwebsite=http://example.i2p/?a=b\nno equals sign\n"))
+	if assert.Nil(err) {
+		assert.Equal("example", cfg.Name())
+		assert.Equal("dev@mail.i2p", cfg.Signer())
+		assert.Equal("1.0", cfg.Version())
+		assert.Equal("http://example.i2p/?a=b", cfg["website"])
+		assert.Equal(4, len(cfg))
+	}
+}
+
+func TestParseConfigRequiresKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ParseConfig([]byte("name=example\nversion=1.0\n"))
+	assert.Equal(ERR_PLUGIN_CONFIG_MISSING_KEY, err)
+}
+
+func TestParseConfigRejectsBadName(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, name := range []string{"..", "a/b", "a\\b"} {
+		_, err := ParseConfig([]byte("name=" + name + "\nsigner=dev@mail.i2p\nversion=1.0\n"))
+		assert.Equal(ERR_PLUGIN_CONFIG_BAD_NAME, err, name)
+	}
+}
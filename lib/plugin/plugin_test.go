@@ -0,0 +1,150 @@
+package plugin
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type pluginFile struct {
+	name string
+	data string
+}
+
+// a plugin su3 holding files, signed by priv as signer_id
+func buildPluginSU3(t *testing.T, priv *ecdsa.PrivateKey, signer_id string, files ...pluginFile) []byte {
+	buff := new(bytes.Buffer)
+	archive := zip.NewWriter(buff)
+	for _, file := range files {
+		w, _ := archive.Create(file.name)
+		w.Write([]byte(file.data))
+	}
+	archive.Close()
+	content := buff.Bytes()
+
+	version := make([]byte, 16)
+	copy(version, "1.0")
+
+	su3 := []byte("I2Psu3")
+	su3 = append(su3, 0x00, 0x00)
+	su3 = append(su3, 0x00, 0x01)
+	su3 = append(su3, 0x00, 64)
+	su3 = append(su3, 0x00, byte(len(version)))
+	su3 = append(su3, 0x00, byte(len(signer_id)))
+	content_length := make([]byte, 8)
+	binary.BigEndian.PutUint64(content_length, uint64(len(content)))
+	su3 = append(su3, content_length...)
+	su3 = append(su3, 0x00, 0x00)
+	su3 = append(su3, 0x00, 0x02)
+	su3 = append(su3, make([]byte, 12)...)
+	su3 = append(su3, version...)
+	su3 = append(su3, signer_id...)
+	su3 = append(su3, content...)
+
+	digest := sha256.Sum256(su3)
+	r, s, err := ecdsa.Sign(rand.Reader, priv, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return append(su3, signature...)
+}
+
+const testPluginConfig = "name=example\nsigner=dev@mail.i2p\nversion=1.0\ndescription=an example plugin\n"
+
+func TestInstallExtractsPlugin(t *testing.T) {
+	assert := assert.New(t)
+
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	data := buildPluginSU3(t, priv, "dev@mail.i2p",
+		pluginFile{PLUGIN_CONFIG_FILENAME, testPluginConfig},
+		pluginFile{"lib/example.jar", "jar"},
+		pluginFile{"webapps/", ""},
+		pluginFile{"webapps/example.war", "war"},
+	)
+	dir := t.TempDir()
+	cfg, err := Install(data, &priv.PublicKey, dir)
+	if !assert.Nil(err) {
+		return
+	}
+	assert.Equal("example", cfg.Name())
+	assert.Equal("1.0", cfg.Version())
+	assert.Equal("an example plugin", cfg["description"])
+	for name, expected := range map[string]string{
+		PLUGIN_CONFIG_FILENAME: testPluginConfig,
+		"lib/example.jar":      "jar",
+		"webapps/example.war":  "war",
+	} {
+		got, err := ioutil.ReadFile(filepath.Join(dir, "example", filepath.FromSlash(name)))
+		assert.Nil(err, name)
+		assert.Equal(expected, string(got), name)
+	}
+	entries, _ := ioutil.ReadDir(dir)
+	assert.Equal(1, len(entries), "only the plugin directory should be left")
+
+	_, err = Install(data, &priv.PublicKey, dir)
+	assert.Equal(ERR_PLUGIN_ALREADY_INSTALLED, err)
+}
+
+func TestInstallRejectsOtherSigningKey(t *testing.T) {
+	assert := assert.New(t)
+
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	data := buildPluginSU3(t, priv, "dev@mail.i2p", pluginFile{PLUGIN_CONFIG_FILENAME, testPluginConfig})
+	dir := t.TempDir()
+	_, err := Install(data, &other.PublicKey, dir)
+	assert.NotNil(err)
+	_, serr := os.Stat(filepath.Join(dir, "example"))
+	assert.True(os.IsNotExist(serr))
+}
+
+func TestInstallRejectsSignerMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	data := buildPluginSU3(t, priv, "other@mail.i2p", pluginFile{PLUGIN_CONFIG_FILENAME, testPluginConfig})
+	_, err := Install(data, &priv.PublicKey, t.TempDir())
+	assert.Equal(ERR_PLUGIN_SIGNER_MISMATCH, err)
+}
+
+func TestInstallRequiresPluginConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	data := buildPluginSU3(t, priv, "dev@mail.i2p", pluginFile{"lib/example.jar", "jar"})
+	_, err := Install(data, &priv.PublicKey, t.TempDir())
+	assert.Equal(ERR_PLUGIN_CONFIG_NOT_FOUND, err)
+}
+
+func TestInstallRejectsPathsOutsidePlugin(t *testing.T) {
+	assert := assert.New(t)
+
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	for _, name := range []string{"../escaped", "lib/../../escaped", "/escaped"} {
+		data := buildPluginSU3(t, priv, "dev@mail.i2p",
+			pluginFile{PLUGIN_CONFIG_FILENAME, testPluginConfig},
+			pluginFile{name, "escaped"},
+		)
+		root := t.TempDir()
+		dir := filepath.Join(root, "plugins")
+		os.Mkdir(dir, 0700)
+		_, err := Install(data, &priv.PublicKey, dir)
+		assert.Equal(ERR_PLUGIN_BAD_PATH, err, name)
+		_, serr := os.Stat(filepath.Join(root, "escaped"))
+		assert.True(os.IsNotExist(serr), name)
+		entries, _ := ioutil.ReadDir(dir)
+		assert.Equal(0, len(entries), "a failed install should leave nothing behind")
+	}
+}
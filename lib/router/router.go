@@ -1,18 +1,28 @@
 package router
 
 import (
+	"github.com/go-i2p/go-i2p/lib/common"
 	"github.com/go-i2p/go-i2p/lib/config"
 	"github.com/go-i2p/go-i2p/lib/netdb"
+	"github.com/go-i2p/go-i2p/lib/util"
 	log "github.com/sirupsen/logrus"
 	"time"
 )
 
+// how often the router expires the entries of its stores
+const (
+	REAP_INTERVAL = time.Minute
+	REAP_JITTER   = 10 * time.Second
+)
+
 // i2p router type
 type Router struct {
-	cfg       *config.RouterConfig
-	ndb       netdb.StdNetDB
-	closeChnl chan bool
-	running   bool
+	cfg        *config.RouterConfig
+	ndb        netdb.StdNetDB
+	reaper     *util.Reaper
+	stopReaper chan struct{}
+	closeChnl  chan bool
+	running    bool
 }
 
 // create router with default configuration
@@ -24,9 +34,20 @@ func CreateRouter() (r *Router, err error) {
 
 // create router from configuration
 func FromConfig(c *config.RouterConfig) (r *Router, err error) {
+	err = c.Validate()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"at":     "router.FromConfig",
+			"reason": err.Error(),
+		}).Error("invalid router configuration")
+		return
+	}
 	r = new(Router)
 	r.cfg = c
 	r.closeChnl = make(chan bool)
+	r.reaper = util.NewReaper()
+	common.RegisterParseLog(r.reaper)
+	r.reaper.Register("router info verify cache", netdb.RouterInfoVerifyCache)
 	return
 }
 
@@ -37,6 +58,10 @@ func (r *Router) Wait() {
 
 // Stop starts stopping internal state of router
 func (r *Router) Stop() {
+	if r.stopReaper != nil {
+		close(r.stopReaper)
+		r.stopReaper = nil
+	}
 	r.closeChnl <- true
 	r.running = false
 }
@@ -56,6 +81,8 @@ func (r *Router) Start() {
 		return
 	}
 	r.running = true
+	r.stopReaper = make(chan struct{})
+	go r.reaper.Run(REAP_INTERVAL, REAP_JITTER, r.stopReaper)
 	go r.mainloop()
 }
 
@@ -0,0 +1,119 @@
+package transport
+
+import (
+	"fmt"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/transport/ssu"
+	"strings"
+	"time"
+)
+
+// SSU RouterAddress options that name an introducer, present when the router is firewalled
+// SSU2 addresses are classified by ssu.ClassifyAddress instead
+var introducerOptions = []string{"ihost0", "itag0"}
+
+// the answers to "why can't I reach this router?"
+type Diagnosis struct {
+	// transports we run
+	Ours []string
+	// transport styles the router offers
+	Offered []string
+	// true if one of our transports is compatable with the router
+	Compatable bool
+	// styles of addresses whose expiration has passed
+	Expired []string
+	// true if the router publishes the U capability
+	Unreachable bool
+	// styles of addresses that can only be dialed through introducers
+	NeedsIntroducers []string
+	// true if the router is banned or blocklisted
+	Banned bool
+	// true if we have a route to the router
+	Routed bool
+}
+
+// return a diagnosis of why we may be unable to reach a router as of now
+// banned and routed answer whether a router hash is banned and whether we have a route to it
+// either may be nil if we do not know, in which case the router is taken as not banned and routed
+func (tmux *TransportMuxer) Diagnose(routerInfo common.RouterInfo, now time.Time, banned, routed func(common.Hash) bool) (diag Diagnosis) {
+	for _, t := range tmux.trans {
+		diag.Ours = append(diag.Ours, t.Name())
+	}
+	diag.Compatable = tmux.Compatable(routerInfo)
+	diag.Unreachable = routerInfo.HasCapability(common.CAPS_UNREACHABLE)
+	addresses, _ := routerInfo.RouterAddresses()
+	for _, address := range addresses {
+		str, err := address.TransportStyle()
+		if err != nil {
+			continue
+		}
+		style, _ := str.Data()
+		diag.Offered = append(diag.Offered, style)
+		expiration, _ := address.Expiration()
+		if expiration != (common.Date{}) && expiration.Time().Before(now) {
+			diag.Expired = append(diag.Expired, style)
+		}
+		if needsIntroducers(address) {
+			diag.NeedsIntroducers = append(diag.NeedsIntroducers, style)
+		}
+	}
+	hash, err := routerInfo.IdentHash()
+	if err == nil && banned != nil {
+		diag.Banned = banned(hash)
+	}
+	diag.Routed = true
+	if err == nil && routed != nil {
+		diag.Routed = routed(hash)
+	}
+	return
+}
+
+func needsIntroducers(address common.RouterAddress) bool {
+	if info, err := ssu.ClassifyAddress(address); err != ssu.ERR_NOT_SSU2_ADDRESS {
+		return err == nil && info.Role == ssu.ADDRESS_NEEDS_INTRODUCER
+	}
+	options, err := address.Options()
+	if err != nil || options == nil {
+		return false
+	}
+	values, _ := options.Values()
+	for _, key := range introducerOptions {
+		if values.Get(key) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// return a human readable reason for every problem found, empty if none were
+func (diag Diagnosis) Reasons() (reasons []string) {
+	if !diag.Compatable {
+		reasons = append(reasons, fmt.Sprintf("no compatable transport: we run [%s], it offers [%s]",
+			strings.Join(diag.Ours, ", "), strings.Join(diag.Offered, ", ")))
+	}
+	if len(diag.Expired) > 0 {
+		reasons = append(reasons, fmt.Sprintf("expired addresses: [%s]", strings.Join(diag.Expired, ", ")))
+	}
+	if diag.Unreachable {
+		reasons = append(reasons, "router publishes itself as unreachable")
+	}
+	if len(diag.NeedsIntroducers) > 0 {
+		reasons = append(reasons, fmt.Sprintf("firewalled, needs introducers: [%s]", strings.Join(diag.NeedsIntroducers, ", ")))
+	}
+	if diag.Banned {
+		reasons = append(reasons, "router is banned or blocklisted")
+	}
+	if !diag.Routed {
+		reasons = append(reasons, "we have no route to the router")
+	}
+	return
+}
+
+// return the reasons one per line
+func (diag Diagnosis) String() string {
+	reasons := diag.Reasons()
+	if len(reasons) == 0 {
+		return "no reason found that the router is unreachable"
+	}
+	return strings.Join(reasons, "\n")
+}
@@ -0,0 +1,75 @@
+package transport
+
+import (
+	"encoding/binary"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/internal/fixture"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func buildDiagnoseRouterInfo(caps string, addresses ...common.RouterAddress) common.RouterInfo {
+	return fixture.RouterInfo{
+		Addresses: addresses,
+		Options:   map[string]string{"caps": caps},
+	}.Build()
+}
+
+func TestDiagnoseReachableRouter(t *testing.T) {
+	assert := assert.New(t)
+
+	tmux := Mux(&styleTransport{testTransport{name: "NTCP2"}})
+	routerInfo := buildDiagnoseRouterInfo("LR", fixture.Address(0, "NTCP2", map[string]string{"host": "127.0.0.1"}))
+	diag := tmux.Diagnose(routerInfo, time.Now(), nil, nil)
+	assert.True(diag.Compatable)
+	assert.Equal([]string{"NTCP2"}, diag.Offered)
+	assert.Empty(diag.Reasons())
+}
+
+func TestDiagnoseReportsUnreachableReasons(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	var expired common.Date
+	binary.BigEndian.PutUint64(expired[:], uint64(now.Add(-time.Hour).UnixNano()/int64(time.Millisecond)))
+	tmux := Mux(&styleTransport{testTransport{name: "NTCP2"}})
+	routerInfo := buildDiagnoseRouterInfo("LU",
+		fixture.ExpiringAddress(0, expired, "SSU2", map[string]string{"host": "127.0.0.1"}),
+		fixture.Address(0, "SSU", map[string]string{"ihost0": "10.0.0.1", "itag0": "1234"}),
+	)
+	hash, _ := routerInfo.IdentHash()
+	banned := func(h common.Hash) bool { return h == hash }
+	routed := func(common.Hash) bool { return false }
+	diag := tmux.Diagnose(routerInfo, now, banned, routed)
+
+	assert.Equal(Diagnosis{
+		Ours:             []string{"NTCP2"},
+		Offered:          []string{"SSU2", "SSU"},
+		Expired:          []string{"SSU2"},
+		Unreachable:      true,
+		NeedsIntroducers: []string{"SSU"},
+		Banned:           true,
+	}, diag)
+	assert.Equal([]string{
+		"no compatable transport: we run [NTCP2], it offers [SSU2, SSU]",
+		"expired addresses: [SSU2]",
+		"router publishes itself as unreachable",
+		"firewalled, needs introducers: [SSU]",
+		"router is banned or blocklisted",
+		"we have no route to the router",
+	}, diag.Reasons())
+}
+
+func TestDiagnoseClassifiesSSU2Addresses(t *testing.T) {
+	assert := assert.New(t)
+
+	tmux := Mux(&styleTransport{testTransport{name: "SSU2"}})
+	routerInfo := buildDiagnoseRouterInfo("R",
+		fixture.Address(0, "SSU2", map[string]string{"caps": "4", "ih0": "aaaa", "itag0": "1", "ih1": "bbbb", "itag1": "2"}),
+		fixture.Address(1, "SSU2", map[string]string{"host": "192.0.2.1", "port": "9000", "ih0": "aaaa", "itag0": "1"}),
+		fixture.Address(2, "SSU2", map[string]string{"caps": "4", "itag0": "1"}),
+	)
+	diag := tmux.Diagnose(routerInfo, time.Now(), nil, nil)
+	assert.Equal([]string{"SSU2"}, diag.NeedsIntroducers, "only the firewalled address with introducers needs them")
+}
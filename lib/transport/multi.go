@@ -2,6 +2,7 @@ package transport
 
 import (
 	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/config"
 )
 
 // muxes multiple transports into 1 Transport
@@ -54,7 +55,47 @@ func (tmux *TransportMuxer) Name() string {
 // return session and nil if successful
 // return nil and ErrNoTransportAvailable if we failed to get a session
 func (tmux *TransportMuxer) GetSession(routerInfo common.RouterInfo) (s TransportSession, err error) {
-	for _, t := range tmux.trans {
+	return getSession(routerInfo, tmux.trans)
+}
+
+// get a transport session given a router info, trying the transports whose Name() is in
+// preferred first, in that order, then the rest in their usual order
+// lets a client session hint i.e. SSU for latency sensitive traffic or NTCP for bulk transfers
+// return session and nil if successful
+// return nil and ErrNoTransportAvailable if we failed to get a session
+func (tmux *TransportMuxer) GetSessionPreferring(routerInfo common.RouterInfo, preferred ...string) (s TransportSession, err error) {
+	ordered := make([]Transport, 0, len(tmux.trans))
+	used := make(map[int]bool)
+	for _, name := range preferred {
+		for idx, t := range tmux.trans {
+			if !used[idx] && t.Name() == name {
+				ordered = append(ordered, t)
+				used[idx] = true
+			}
+		}
+	}
+	for idx, t := range tmux.trans {
+		if !used[idx] {
+			ordered = append(ordered, t)
+		}
+	}
+	return getSession(routerInfo, ordered)
+}
+
+// get a transport session given a router info for a client session, honoring the
+// transport preference of its configuration
+// return session and nil if successful
+// return nil and ErrNoTransportAvailable if we failed to get a session
+func (tmux *TransportMuxer) GetSessionFor(routerInfo common.RouterInfo, cfg *config.SessionConfig) (s TransportSession, err error) {
+	if cfg == nil {
+		return tmux.GetSession(routerInfo)
+	}
+	return tmux.GetSessionPreferring(routerInfo, cfg.PreferredTransports...)
+}
+
+// get a session from the first compatable transport in trans that gives us one
+func getSession(routerInfo common.RouterInfo, trans []Transport) (s TransportSession, err error) {
+	for _, t := range trans {
 		// pick the first one that is compatable
 		if t.Compatable(routerInfo) {
 			// try to get a session
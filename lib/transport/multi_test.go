@@ -0,0 +1,129 @@
+package transport
+
+import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/config"
+	"github.com/go-i2p/go-i2p/lib/i2np"
+	"github.com/go-i2p/go-i2p/lib/internal/fixture"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testSession struct {
+	name string
+}
+
+func (s *testSession) QueueSendI2NP(msg i2np.I2NPMessage)      {}
+func (s *testSession) SendQueueSize() int                      { return 0 }
+func (s *testSession) ReadNextI2NP() (i2np.I2NPMessage, error) { return i2np.I2NPMessage{}, nil }
+func (s *testSession) Close() error                            { return nil }
+
+type testTransport struct {
+	name   string
+	compat bool
+	fail   bool
+	tried  *[]string
+}
+
+func (t *testTransport) SetIdentity(ident common.RouterIdentity) error { return nil }
+func (t *testTransport) Compatable(routerInfo common.RouterInfo) bool  { return t.compat }
+func (t *testTransport) Close() error                                  { return nil }
+func (t *testTransport) Name() string                                  { return t.name }
+func (t *testTransport) GetSession(routerInfo common.RouterInfo) (TransportSession, error) {
+	*t.tried = append(*t.tried, t.name)
+	if t.fail {
+		return nil, errors.New("dial failed")
+	}
+	return &testSession{name: t.name}, nil
+}
+
+func TestGetSessionPreferringTriesHintedTransportFirst(t *testing.T) {
+	assert := assert.New(t)
+
+	tried := []string{}
+	tmux := Mux(
+		&testTransport{name: "NTCP2", compat: true, tried: &tried},
+		&testTransport{name: "SSU2", compat: true, tried: &tried},
+	)
+	s, err := tmux.GetSessionPreferring(common.RouterInfo{}, "SSU2")
+	assert.Nil(err)
+	assert.Equal("SSU2", s.(*testSession).name)
+	assert.Equal([]string{"SSU2"}, tried)
+
+	tried = tried[:0]
+	s, err = tmux.GetSession(common.RouterInfo{})
+	assert.Nil(err)
+	assert.Equal("NTCP2", s.(*testSession).name, "unhinted dial should keep the muxer order")
+}
+
+func TestGetSessionPreferringFallsBack(t *testing.T) {
+	assert := assert.New(t)
+
+	tried := []string{}
+	tmux := Mux(
+		&testTransport{name: "NTCP2", compat: true, tried: &tried},
+		&testTransport{name: "SSU2", compat: true, fail: true, tried: &tried},
+	)
+	s, err := tmux.GetSessionPreferring(common.RouterInfo{}, "SSU2")
+	assert.Nil(err)
+	assert.Equal("NTCP2", s.(*testSession).name)
+	assert.Equal([]string{"SSU2", "NTCP2"}, tried)
+
+	tmux = Mux(&testTransport{name: "NTCP2", compat: false, tried: &tried})
+	_, err = tmux.GetSessionPreferring(common.RouterInfo{}, "NTCP2")
+	assert.Equal(ErrNoTransportAvailable, err)
+}
+
+func TestGetSessionForHonorsSessionConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	tried := []string{}
+	tmux := Mux(
+		&testTransport{name: "NTCP2", compat: true, tried: &tried},
+		&testTransport{name: "SSU2", compat: true, tried: &tried},
+	)
+	s, err := tmux.GetSessionFor(common.RouterInfo{}, &config.SessionConfig{PreferredTransports: []string{"SSU2"}})
+	assert.Nil(err)
+	assert.Equal("SSU2", s.(*testSession).name)
+	assert.Equal([]string{"SSU2"}, tried)
+
+	for _, cfg := range []*config.SessionConfig{nil, &config.DefaultSessionConfig} {
+		tried = tried[:0]
+		s, err = tmux.GetSessionFor(common.RouterInfo{}, cfg)
+		assert.Nil(err)
+		assert.Equal("NTCP2", s.(*testSession).name, "no preference should keep the muxer order")
+		assert.Equal([]string{"NTCP2"}, tried)
+	}
+}
+
+// a transport that can only dial addresses of its own style
+type styleTransport struct {
+	testTransport
+}
+
+func (t *styleTransport) Compatable(routerInfo common.RouterInfo) bool {
+	return len(routerInfo.RouterAddressesByStyle(t.name)) > 0
+}
+
+func buildStyledRouterInfo(styles ...string) common.RouterInfo {
+	spec := fixture.RouterInfo{}
+	for _, style := range styles {
+		spec.Addresses = append(spec.Addresses, fixture.Address(0, style, map[string]string{"host": "127.0.0.1", "port": "4567"}))
+	}
+	return spec.Build()
+}
+
+func TestGetSessionSkipsUnknownTransportStyle(t *testing.T) {
+	assert := assert.New(t)
+
+	tried := []string{}
+	tmux := Mux(&styleTransport{testTransport{name: "NTCP2", tried: &tried}})
+
+	s, err := tmux.GetSession(buildStyledRouterInfo("NTCP3", "NTCP2"))
+	assert.Nil(err)
+	assert.Equal("NTCP2", s.(*testSession).name)
+
+	_, err = tmux.GetSession(buildStyledRouterInfo("NTCP3"))
+	assert.Equal(ErrNoTransportAvailable, err)
+}
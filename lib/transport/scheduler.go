@@ -0,0 +1,101 @@
+package transport
+
+import (
+	"github.com/go-i2p/go-i2p/lib/i2np"
+	"sync"
+)
+
+// how urgently an outbound i2np message should be sent
+type Priority int
+
+const (
+	// latency sensitive control messages, tunnel builds and delivery status
+	PRIORITY_HIGH Priority = iota
+	// netdb traffic and garlic messages
+	PRIORITY_NORMAL
+	// tunnel data, which must not starve the other classes
+	PRIORITY_BULK
+	priority_classes
+)
+
+// return the priority class for an i2np message type
+func MessagePriority(message_type int) Priority {
+	switch message_type {
+	case i2np.I2NP_MESSAGE_TYPE_DELIVERY_STATUS,
+		i2np.I2NP_MESSAGE_TYPE_TUNNEL_BUILD,
+		i2np.I2NP_MESSAGE_TYPE_TUNNEL_BUILD_REPLY,
+		i2np.I2NP_MESSAGE_TYPE_VARIABLE_TUNNEL_BUILD,
+		i2np.I2NP_MESSAGE_TYPE_VARIABLE_TUNNEL_BUILD_REPLY:
+		return PRIORITY_HIGH
+	case i2np.I2NP_MESSAGE_TYPE_TUNNEL_DATA,
+		i2np.I2NP_MESSAGE_TYPE_TUNNEL_GATEWAY:
+		return PRIORITY_BULK
+	}
+	return PRIORITY_NORMAL
+}
+
+// an outbound i2np message waiting for its session
+type scheduledMessage struct {
+	session TransportSession
+	msg     i2np.I2NPMessage
+}
+
+// Scheduler orders outbound i2np messages across sessions by priority class above the
+// transports, so that queued bulk traffic does not delay control messages
+// messages of the same class are sent in the order they were queued
+type Scheduler struct {
+	access sync.Mutex
+	queues [priority_classes][]scheduledMessage
+}
+
+// create a Scheduler with nothing queued
+func NewScheduler() *Scheduler {
+	return new(Scheduler)
+}
+
+// queue an i2np message to be sent over session, classed by its type byte
+func (scheduler *Scheduler) Enqueue(session TransportSession, msg i2np.I2NPMessage) {
+	priority := PRIORITY_NORMAL
+	if len(msg) > 0 {
+		priority = MessagePriority(int(msg[0]))
+	}
+	scheduler.access.Lock()
+	scheduler.queues[priority] = append(scheduler.queues[priority], scheduledMessage{
+		session: session,
+		msg:     msg,
+	})
+	scheduler.access.Unlock()
+}
+
+// hand the most urgent queued message to its session
+// returns false if nothing was queued
+func (scheduler *Scheduler) SendNext() bool {
+	scheduler.access.Lock()
+	var next scheduledMessage
+	found := false
+	for priority := range scheduler.queues {
+		queue := scheduler.queues[priority]
+		if len(queue) > 0 {
+			next = queue[0]
+			queue[0] = scheduledMessage{}
+			scheduler.queues[priority] = queue[1:]
+			found = true
+			break
+		}
+	}
+	scheduler.access.Unlock()
+	if found {
+		next.session.QueueSendI2NP(next.msg)
+	}
+	return found
+}
+
+// return how many messages are queued in every class
+func (scheduler *Scheduler) Size() (count int) {
+	scheduler.access.Lock()
+	for _, queue := range scheduler.queues {
+		count += len(queue)
+	}
+	scheduler.access.Unlock()
+	return
+}
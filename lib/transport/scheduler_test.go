@@ -0,0 +1,50 @@
+package transport
+
+import (
+	"github.com/go-i2p/go-i2p/lib/i2np"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type recordingSession struct {
+	testSession
+	sent *[]i2np.I2NPMessage
+}
+
+func (s *recordingSession) QueueSendI2NP(msg i2np.I2NPMessage) {
+	*s.sent = append(*s.sent, msg)
+}
+
+func TestSchedulerSendsHighPriorityAheadOfBulk(t *testing.T) {
+	assert := assert.New(t)
+
+	sent := []i2np.I2NPMessage{}
+	first := &recordingSession{sent: &sent}
+	second := &recordingSession{sent: &sent}
+	bulk_a := i2np.I2NPMessage{i2np.I2NP_MESSAGE_TYPE_TUNNEL_DATA, 0x01}
+	bulk_b := i2np.I2NPMessage{i2np.I2NP_MESSAGE_TYPE_TUNNEL_DATA, 0x02}
+	store := i2np.I2NPMessage{i2np.I2NP_MESSAGE_TYPE_DATABASE_STORE}
+	reply := i2np.I2NPMessage{i2np.I2NP_MESSAGE_TYPE_VARIABLE_TUNNEL_BUILD_REPLY}
+	status := i2np.I2NPMessage{i2np.I2NP_MESSAGE_TYPE_DELIVERY_STATUS}
+
+	scheduler := NewScheduler()
+	scheduler.Enqueue(first, bulk_a)
+	scheduler.Enqueue(second, bulk_b)
+	scheduler.Enqueue(first, store)
+	scheduler.Enqueue(second, reply)
+	scheduler.Enqueue(first, status)
+	assert.Equal(5, scheduler.Size())
+
+	for scheduler.SendNext() {
+	}
+	assert.Equal([]i2np.I2NPMessage{reply, status, store, bulk_a, bulk_b}, sent)
+	assert.Equal(0, scheduler.Size())
+}
+
+func TestMessagePriorityClasses(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(PRIORITY_HIGH, MessagePriority(i2np.I2NP_MESSAGE_TYPE_TUNNEL_BUILD_REPLY))
+	assert.Equal(PRIORITY_NORMAL, MessagePriority(i2np.I2NP_MESSAGE_TYPE_GARLIC))
+	assert.Equal(PRIORITY_BULK, MessagePriority(i2np.I2NP_MESSAGE_TYPE_TUNNEL_GATEWAY))
+}
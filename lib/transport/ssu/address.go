@@ -0,0 +1,89 @@
+package ssu
+
+import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// how we can connect to the router publishing an SSU2 address
+type AddressRole int
+
+const (
+	// no host and no introducers, we cannot connect
+	ADDRESS_UNUSABLE AddressRole = iota
+	// a published host and port we can connect to directly
+	ADDRESS_DIRECT
+	// firewalled, we must be introduced by one of the published introducers
+	ADDRESS_NEEDS_INTRODUCER
+)
+
+// SSU2 address caps
+const (
+	SSU2_CAPS_PEER_TEST  = 'B'
+	SSU2_CAPS_INTRODUCER = 'C'
+	SSU2_CAPS_IPV4       = '4'
+	SSU2_CAPS_IPV6       = '6'
+)
+
+var ERR_NOT_SSU2_ADDRESS = errors.New("router address is not an SSU2 address")
+
+// what an SSU2 RouterAddress tells us about reaching its router
+type AddressInfo struct {
+	Role AddressRole
+	// the router can act as an introducer for firewalled routers
+	Introducer bool
+	// the router can take part in peer tests
+	PeerTest bool
+	IPv4     bool
+	IPv6     bool
+	// how many introducers are published
+	Introducers int
+}
+
+// classify an SSU2 RouterAddress by whether we can connect directly, must be introduced
+// or cannot connect at all, and what else it is capable of
+// returns ERR_NOT_SSU2_ADDRESS for addresses of other transports
+func ClassifyAddress(address common.RouterAddress) (info AddressInfo, err error) {
+	style, err := address.TransportStyle()
+	if err != nil {
+		return
+	}
+	if name, _ := style.Data(); name != common.TRANSPORT_STYLE_SSU2 {
+		err = ERR_NOT_SSU2_ADDRESS
+		return
+	}
+	var values common.MappingValues
+	if options, _ := address.Options(); len(options) > 0 {
+		values, _ = options.Values()
+	}
+	caps := optionString(values, "caps")
+	info.Introducer = strings.ContainsRune(caps, SSU2_CAPS_INTRODUCER)
+	info.PeerTest = strings.ContainsRune(caps, SSU2_CAPS_PEER_TEST)
+	info.IPv4 = strings.ContainsRune(caps, SSU2_CAPS_IPV4)
+	info.IPv6 = strings.ContainsRune(caps, SSU2_CAPS_IPV6)
+	for optionString(values, "ih"+strconv.Itoa(info.Introducers)) != "" {
+		info.Introducers++
+	}
+	ip := net.ParseIP(optionString(values, "host"))
+	switch {
+	case ip != nil && optionString(values, "port") != "":
+		info.Role = ADDRESS_DIRECT
+		info.IPv4 = ip.To4() != nil
+		info.IPv6 = !info.IPv4
+	case info.Introducers > 0:
+		info.Role = ADDRESS_NEEDS_INTRODUCER
+	default:
+		info.Role = ADDRESS_UNUSABLE
+	}
+	return
+}
+
+func optionString(values common.MappingValues, key string) (str string) {
+	if value := values.Get(key); value != nil {
+		str, _ = value.Data()
+	}
+	return
+}
@@ -0,0 +1,55 @@
+package ssu
+
+import (
+	"github.com/go-i2p/go-i2p/lib/internal/fixture"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestClassifySSU2Addresses(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name    string
+		options map[string]string
+		info    AddressInfo
+	}{
+		{
+			"direct IPv4 introducer",
+			map[string]string{"host": "192.0.2.1", "port": "9000", "caps": "BC"},
+			AddressInfo{Role: ADDRESS_DIRECT, Introducer: true, PeerTest: true, IPv4: true},
+		},
+		{
+			"direct IPv6",
+			map[string]string{"host": "2001:db8::1", "port": "9000"},
+			AddressInfo{Role: ADDRESS_DIRECT, IPv6: true},
+		},
+		{
+			"firewalled with introducers",
+			map[string]string{"caps": "4", "ih0": "aaaa", "itag0": "1", "ih1": "bbbb", "itag1": "2"},
+			AddressInfo{Role: ADDRESS_NEEDS_INTRODUCER, IPv4: true, Introducers: 2},
+		},
+		{
+			"firewalled without introducers",
+			map[string]string{"caps": "6"},
+			AddressInfo{Role: ADDRESS_UNUSABLE, IPv6: true},
+		},
+		{
+			"host without port",
+			map[string]string{"host": "192.0.2.1"},
+			AddressInfo{Role: ADDRESS_UNUSABLE},
+		},
+	}
+	for _, test := range tests {
+		info, err := ClassifyAddress(fixture.Address(0, "SSU2", test.options))
+		assert.Nil(err, test.name)
+		assert.Equal(test.info, info, test.name)
+	}
+}
+
+func TestClassifyAddressRejectsOtherTransports(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ClassifyAddress(fixture.Address(0, "NTCP2", map[string]string{"host": "192.0.2.1", "port": "9000"}))
+	assert.Equal(ERR_NOT_SSU2_ADDRESS, err)
+}
@@ -0,0 +1,133 @@
+package ssu
+
+import (
+	"container/list"
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+)
+
+// how long a token we issue in a Retry may be used for
+const DEFAULT_TOKEN_LIFETIME = time.Minute
+
+// how many issued tokens we keep before evicting the oldest
+const DEFAULT_MAX_TOKENS = 4096
+
+// a token we issued, bound to the address it was sent to
+type issuedToken struct {
+	token   uint64
+	addr    string
+	expires time.Time
+}
+
+// TokenStore issues and checks the SSU2 tokens a responder hands out in Retry messages
+// so that it only sends a SessionCreated to a source that has shown it can receive
+// tokens are bound to the address they were sent to and can be used once
+// at most MaxTokens are kept so a flood of spoofed SessionRequests cannot grow the store,
+// once full the oldest token is evicted for each new one
+type TokenStore struct {
+	// how long an issued token stays valid
+	Lifetime time.Duration
+	// how many issued tokens are kept
+	MaxTokens int
+	access    sync.Mutex
+	tokens    map[uint64]*list.Element
+	// issued tokens, oldest first
+	order *list.List
+}
+
+// create a TokenStore with no tokens issued
+func NewTokenStore() (store *TokenStore) {
+	store = &TokenStore{
+		Lifetime:  DEFAULT_TOKEN_LIFETIME,
+		MaxTokens: DEFAULT_MAX_TOKENS,
+		tokens:    make(map[uint64]*list.Element),
+		order:     list.New(),
+	}
+	return
+}
+
+// issue a new token for addr, never zero as zero means no token in a SessionRequest
+func (store *TokenStore) Issue(addr net.Addr, now time.Time) (token uint64, err error) {
+	buff := make([]byte, 8)
+	store.access.Lock()
+	defer store.access.Unlock()
+	for {
+		if _, err = rand.Read(buff); err != nil {
+			return
+		}
+		token = binary.BigEndian.Uint64(buff)
+		if _, ok := store.tokens[token]; token != 0 && !ok {
+			break
+		}
+	}
+	for store.order.Len() >= store.MaxTokens && store.order.Len() > 0 {
+		store.remove(store.order.Front())
+	}
+	store.tokens[token] = store.order.PushBack(issuedToken{
+		token:   token,
+		addr:    addr.String(),
+		expires: now.Add(store.Lifetime),
+	})
+	return
+}
+
+// return how many issued tokens are outstanding
+func (store *TokenStore) Size() (count int) {
+	store.access.Lock()
+	count = store.order.Len()
+	store.access.Unlock()
+	return
+}
+
+// forget an issued token, the caller holds access
+func (store *TokenStore) remove(element *list.Element) {
+	delete(store.tokens, element.Value.(issuedToken).token)
+	store.order.Remove(element)
+}
+
+// use up a token presented by addr, returning true if we issued it to addr and it has not
+// expired or been used before
+func (store *TokenStore) Redeem(addr net.Addr, token uint64, now time.Time) bool {
+	if token == 0 {
+		return false
+	}
+	store.access.Lock()
+	defer store.access.Unlock()
+	element, ok := store.tokens[token]
+	if !ok {
+		return false
+	}
+	issued := element.Value.(issuedToken)
+	if issued.addr != addr.String() {
+		return false
+	}
+	store.remove(element)
+	return now.Before(issued.expires)
+}
+
+// decide whether a SessionRequest from addr carrying token may proceed
+// returns a zero retry_token if it may, otherwise the token to send back to addr in a Retry
+func (store *TokenStore) Admit(addr net.Addr, token uint64, now time.Time) (retry_token uint64, err error) {
+	if store.Redeem(addr, token, now) {
+		return
+	}
+	return store.Issue(addr, now)
+}
+
+// remove every token that expired before now, for use with a util.Reaper
+func (store *TokenStore) Expire(now time.Time) (count int) {
+	store.access.Lock()
+	for element := store.order.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(issuedToken).expires.Before(now) {
+			store.remove(element)
+			count++
+		}
+		element = next
+	}
+	store.access.Unlock()
+	return
+}
@@ -0,0 +1,66 @@
+package ssu
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestAdmitRequiresTokenFromRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	store := NewTokenStore()
+	addr := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 4567}
+
+	retry_token, err := store.Admit(addr, 0, now)
+	assert.Nil(err)
+	assert.NotZero(retry_token, "a SessionRequest without a token should get a Retry")
+
+	proceed, err := store.Admit(addr, retry_token, now.Add(time.Second))
+	assert.Nil(err)
+	assert.Zero(proceed, "a SessionRequest with the Retry token should proceed")
+
+	replay, _ := store.Admit(addr, retry_token, now.Add(time.Second))
+	assert.NotZero(replay, "a token may only be used once")
+}
+
+func TestRedeemRejectsOtherAddressAndExpiredTokens(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	store := NewTokenStore()
+	addr := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 4567}
+	spoofed := &net.UDPAddr{IP: net.IPv4(198, 51, 100, 7), Port: 4567}
+
+	token, _ := store.Issue(addr, now)
+	assert.False(store.Redeem(spoofed, token, now), "token must be bound to the address it was sent to")
+	assert.True(store.Redeem(addr, token, now))
+
+	token, _ = store.Issue(addr, now)
+	assert.False(store.Redeem(addr, token, now.Add(DEFAULT_TOKEN_LIFETIME+time.Second)))
+
+	store.Issue(addr, now)
+	assert.Equal(1, store.Expire(now.Add(DEFAULT_TOKEN_LIFETIME+time.Second)))
+}
+
+func TestTokenStoreStaysBoundedUnderFlood(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	store := NewTokenStore()
+	store.MaxTokens = 16
+	victim := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 4567}
+	oldest, _ := store.Issue(victim, now)
+	for i := 0; i < 1000; i++ {
+		spoofed := &net.UDPAddr{IP: net.IPv4(198, 51, byte(i>>8), byte(i)), Port: 4567}
+		store.Admit(spoofed, 0, now)
+	}
+	assert.Equal(16, store.Size())
+	assert.False(store.Redeem(victim, oldest, now), "oldest token should have been evicted")
+
+	latest, _ := store.Issue(victim, now)
+	assert.True(store.Redeem(victim, latest, now))
+	assert.Equal(15, store.Size())
+}
@@ -0,0 +1,74 @@
+package tunnel
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/util"
+	log "github.com/sirupsen/logrus"
+)
+
+// Oldest router version able to parse short (ECIES) tunnel build records
+const SHORT_BUILD_MIN_VERSION = "0.9.51"
+
+var shortBuildMinVersion, _ = util.ParseVersion(SHORT_BUILD_MIN_VERSION)
+
+//
+// Return true if the router described by this RouterInfo can be sent short
+// tunnel build records.  The router must publish a router.version at or above
+// SHORT_BUILD_MIN_VERSION and have an X25519 encryption key in its RouterIdentity.
+//
+func SupportsShortBuild(router_info common.RouterInfo) bool {
+	version := router_info.RouterVersion()
+	parsed, err := util.ParseVersion(version)
+	if err != nil || parsed.Compare(shortBuildMinVersion) < 0 {
+		log.WithFields(log.Fields{
+			"at":          "tunnel.SupportsShortBuild",
+			"version":     version,
+			"min_version": SHORT_BUILD_MIN_VERSION,
+			"reason":      "router version missing or too old",
+		}).Debug("hop does not support short build records")
+		return false
+	}
+	router_identity, err := router_info.RouterIdentity()
+	if err != nil {
+		return false
+	}
+	cert, err := router_identity.Certificate()
+	if err != nil {
+		return false
+	}
+	cert_type, _ := cert.Type()
+	if cert_type != common.CERT_KEY {
+		return false
+	}
+	crypto_type, err := common.KeyCertificate(cert).PublicKeyType()
+	return err == nil && crypto_type == common.KEYCERT_CRYPTO_X25519
+}
+
+//
+// Return only the candidate hops that can be sent short tunnel build records,
+// preserving their order.
+//
+func ShortBuildHops(candidates []common.RouterInfo) (hops []common.RouterInfo) {
+	for _, router_info := range candidates {
+		if SupportsShortBuild(router_info) {
+			hops = append(hops, router_info)
+		}
+	}
+	return
+}
+
+//
+// Return true if every hop in a tunnel can be sent short tunnel build records.
+// A single hop that cannot parse them would cause the whole build to fail.
+//
+func CanUseShortBuild(hops []common.RouterInfo) bool {
+	if len(hops) == 0 {
+		return false
+	}
+	for _, router_info := range hops {
+		if !SupportsShortBuild(router_info) {
+			return false
+		}
+	}
+	return true
+}
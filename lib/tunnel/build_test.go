@@ -0,0 +1,59 @@
+package tunnel
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/internal/fixture"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildHopRouterInfo(version string, crypto_type int) common.RouterInfo {
+	return fixture.RouterInfo{
+		SigningKeyType: common.KEYCERT_SIGN_ED25519,
+		CryptoKeyType:  crypto_type,
+		Options:        map[string]string{"router.version": version},
+	}.Build()
+}
+
+func TestSupportsShortBuildWithNewECIESRouter(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildHopRouterInfo("0.9.51", common.KEYCERT_CRYPTO_X25519)
+	assert.True(SupportsShortBuild(router_info))
+}
+
+func TestSupportsShortBuildRejectsOldVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildHopRouterInfo("0.9.48", common.KEYCERT_CRYPTO_X25519)
+	assert.False(SupportsShortBuild(router_info))
+}
+
+func TestSupportsShortBuildRejectsElGamalRouter(t *testing.T) {
+	assert := assert.New(t)
+
+	router_info := buildHopRouterInfo("0.9.53", common.KEYCERT_CRYPTO_ELG)
+	assert.False(SupportsShortBuild(router_info))
+}
+
+func TestShortBuildHopsExcludesTooOldHop(t *testing.T) {
+	assert := assert.New(t)
+
+	newer := buildHopRouterInfo("0.9.52", common.KEYCERT_CRYPTO_X25519)
+	older := buildHopRouterInfo("0.9.50", common.KEYCERT_CRYPTO_X25519)
+	hops := ShortBuildHops([]common.RouterInfo{older, newer})
+	if assert.Equal(1, len(hops)) {
+		assert.Equal(newer, hops[0])
+	}
+	assert.False(CanUseShortBuild([]common.RouterInfo{newer, older}))
+	assert.True(CanUseShortBuild([]common.RouterInfo{newer}))
+}
+
+func TestSupportsShortBuildRejectsUnparsableVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, version := range []string{"", "0.9.51-rc1"} {
+		router_info := buildHopRouterInfo(version, common.KEYCERT_CRYPTO_X25519)
+		assert.False(SupportsShortBuild(router_info), version)
+	}
+}
@@ -0,0 +1,290 @@
+package tunnel
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// where a message is to be delivered
+// garlic cloves and tunnel messages encode these differently on the wire
+const (
+	DELIVERY_LOCAL = iota
+	DELIVERY_DESTINATION
+	DELIVERY_ROUTER
+	DELIVERY_TUNNEL
+)
+
+// size of the delay field in garlic clove delivery instructions
+const GARLIC_DELAY_SIZE = 4
+
+var ERR_DELIVERY_NOT_ENOUGH_DATA = errors.New("not enough delivery instructions data")
+var ERR_DELIVERY_INVALID_MODE = errors.New("delivery mode is not valid for these delivery instructions")
+var ERR_DELIVERY_ENCRYPTED_UNSUPPORTED = errors.New("encrypted garlic clove delivery instructions are not supported")
+
+// delivery instructions decoded from either a garlic clove or a tunnel message
+// so that both subsystems agree on what every field means
+type Delivery struct {
+	Mode     int
+	Hash     common.Hash
+	TunnelID TunnelID
+	// HasDelay is set if a delay was included, Delay is a 4 byte number of seconds
+	// for garlic cloves and a 1 byte DelayFactor for tunnel messages
+	HasDelay bool
+	Delay    int
+	// the remaining fields are only used by tunnel message fragments
+	FollowOn       bool
+	Fragmented     bool
+	MessageID      uint32
+	FragmentNumber int
+	LastFragment   bool
+	Size           uint16
+	// extended options of a tunnel message first fragment, nil if there are none
+	// unimplemented in the Java router
+	ExtendedOptions []byte
+}
+
+// the 2 bit delivery type used in garlic clove delivery instructions
+var garlicModes = map[int]byte{
+	DELIVERY_LOCAL:       0x00,
+	DELIVERY_DESTINATION: 0x01,
+	DELIVERY_ROUTER:      0x02,
+	DELIVERY_TUNNEL:      0x03,
+}
+
+// the 2 bit delivery type used in tunnel message delivery instructions
+// DESTINATION delivery is not possible from a tunnel endpoint
+var tunnelModes = map[int]byte{
+	DELIVERY_LOCAL:  DT_LOCAL,
+	DELIVERY_TUNNEL: DT_TUNNEL,
+	DELIVERY_ROUTER: DT_ROUTER,
+}
+
+func modeFromFlag(modes map[int]byte, value byte) (mode int, err error) {
+	for m, v := range modes {
+		if v == value {
+			mode = m
+			return
+		}
+	}
+	err = ERR_DELIVERY_INVALID_MODE
+	return
+}
+
+// return true if delivery to this mode names a hash
+func (delivery Delivery) hasHash() bool {
+	return delivery.Mode != DELIVERY_LOCAL
+}
+
+// read garlic clove delivery instructions from the start of data
+// returns the decoded Delivery, whatever data follows it and any errors encountered
+func ReadGarlicDelivery(data []byte) (delivery Delivery, remainder []byte, err error) {
+	if len(data) < FLAG_SIZE {
+		err = ERR_DELIVERY_NOT_ENOUGH_DATA
+		return
+	}
+	flag := data[0]
+	if flag&0x80 == 0x80 {
+		err = ERR_DELIVERY_ENCRYPTED_UNSUPPORTED
+		return
+	}
+	delivery.Mode, err = modeFromFlag(garlicModes, (flag&0x60)>>5)
+	if err != nil {
+		return
+	}
+	delivery.HasDelay = flag&0x10 == 0x10
+	remainder = data[FLAG_SIZE:]
+	if delivery.hasHash() {
+		if len(remainder) < HASH_SIZE {
+			err = ERR_DELIVERY_NOT_ENOUGH_DATA
+			return
+		}
+		copy(delivery.Hash[:], remainder[:HASH_SIZE])
+		remainder = remainder[HASH_SIZE:]
+	}
+	if delivery.Mode == DELIVERY_TUNNEL {
+		if len(remainder) < TUNNEL_ID_SIZE {
+			err = ERR_DELIVERY_NOT_ENOUGH_DATA
+			return
+		}
+		delivery.TunnelID = TunnelID(binary.BigEndian.Uint32(remainder[:TUNNEL_ID_SIZE]))
+		remainder = remainder[TUNNEL_ID_SIZE:]
+	}
+	if delivery.HasDelay {
+		if len(remainder) < GARLIC_DELAY_SIZE {
+			err = ERR_DELIVERY_NOT_ENOUGH_DATA
+			return
+		}
+		delivery.Delay = common.Integer(remainder[:GARLIC_DELAY_SIZE])
+		remainder = remainder[GARLIC_DELAY_SIZE:]
+	}
+	return
+}
+
+// encode these delivery instructions as garlic clove delivery instructions
+func (delivery Delivery) GarlicBytes() (data []byte, err error) {
+	mode, ok := garlicModes[delivery.Mode]
+	if !ok {
+		err = ERR_DELIVERY_INVALID_MODE
+		return
+	}
+	flag := mode << 5
+	if delivery.HasDelay {
+		flag |= 0x10
+	}
+	data = append(data, flag)
+	if delivery.hasHash() {
+		data = append(data, delivery.Hash[:]...)
+	}
+	if delivery.Mode == DELIVERY_TUNNEL {
+		tunnel_id := make([]byte, TUNNEL_ID_SIZE)
+		binary.BigEndian.PutUint32(tunnel_id, uint32(delivery.TunnelID))
+		data = append(data, tunnel_id...)
+	}
+	if delivery.HasDelay {
+		delay := make([]byte, GARLIC_DELAY_SIZE)
+		binary.BigEndian.PutUint32(delay, uint32(delivery.Delay))
+		data = append(data, delay...)
+	}
+	return
+}
+
+// read tunnel message delivery instructions, either for a first fragment or in the
+// compressed follow-on fragment form, from the start of data
+// returns the decoded Delivery, whatever data follows it and any errors encountered
+func ReadTunnelDelivery(data []byte) (delivery Delivery, remainder []byte, err error) {
+	if len(data) < FLAG_SIZE {
+		err = ERR_DELIVERY_NOT_ENOUGH_DATA
+		return
+	}
+	flag := data[0]
+	remainder = data[FLAG_SIZE:]
+	if flag&0x80 == 0x80 {
+		delivery.FollowOn = true
+		delivery.Fragmented = true
+		delivery.FragmentNumber = int((flag & 0x7e) >> 1)
+		delivery.LastFragment = flag&0x01 == 0x01
+		if len(remainder) < MESSAGE_ID_SIZE+SIZE_FIELD_SIZE {
+			err = ERR_DELIVERY_NOT_ENOUGH_DATA
+			return
+		}
+		delivery.MessageID = binary.BigEndian.Uint32(remainder[:MESSAGE_ID_SIZE])
+		delivery.Size = binary.BigEndian.Uint16(remainder[MESSAGE_ID_SIZE : MESSAGE_ID_SIZE+SIZE_FIELD_SIZE])
+		remainder = remainder[MESSAGE_ID_SIZE+SIZE_FIELD_SIZE:]
+		return
+	}
+	delivery.Mode, err = modeFromFlag(tunnelModes, (flag&0x60)>>5)
+	if err != nil {
+		return
+	}
+	delivery.HasDelay = flag&0x10 == 0x10
+	delivery.Fragmented = flag&0x08 == 0x08
+	extended_options := flag&0x04 == 0x04
+	if delivery.Mode == DELIVERY_TUNNEL {
+		if len(remainder) < TUNNEL_ID_SIZE {
+			err = ERR_DELIVERY_NOT_ENOUGH_DATA
+			return
+		}
+		delivery.TunnelID = TunnelID(binary.BigEndian.Uint32(remainder[:TUNNEL_ID_SIZE]))
+		remainder = remainder[TUNNEL_ID_SIZE:]
+	}
+	if delivery.hasHash() {
+		if len(remainder) < HASH_SIZE {
+			err = ERR_DELIVERY_NOT_ENOUGH_DATA
+			return
+		}
+		copy(delivery.Hash[:], remainder[:HASH_SIZE])
+		remainder = remainder[HASH_SIZE:]
+	}
+	if delivery.HasDelay {
+		if len(remainder) < DELAY_SIZE {
+			err = ERR_DELIVERY_NOT_ENOUGH_DATA
+			return
+		}
+		delivery.Delay = int(remainder[0])
+		remainder = remainder[DELAY_SIZE:]
+	}
+	if delivery.Fragmented {
+		if len(remainder) < MESSAGE_ID_SIZE {
+			err = ERR_DELIVERY_NOT_ENOUGH_DATA
+			return
+		}
+		delivery.MessageID = binary.BigEndian.Uint32(remainder[:MESSAGE_ID_SIZE])
+		remainder = remainder[MESSAGE_ID_SIZE:]
+	}
+	if extended_options {
+		log.WithFields(log.Fields{
+			"at":   "tunnel.ReadTunnelDelivery",
+			"info": "this feature is unimplemented in the Java router",
+		}).Warn("delivery instructions include extended options")
+		if len(remainder) < 1 || len(remainder) < 1+int(remainder[0]) {
+			err = ERR_DELIVERY_NOT_ENOUGH_DATA
+			return
+		}
+		delivery.ExtendedOptions = remainder[1 : 1+int(remainder[0])]
+		remainder = remainder[1+int(remainder[0]):]
+	}
+	if len(remainder) < SIZE_FIELD_SIZE {
+		err = ERR_DELIVERY_NOT_ENOUGH_DATA
+		return
+	}
+	delivery.Size = binary.BigEndian.Uint16(remainder[:SIZE_FIELD_SIZE])
+	remainder = remainder[SIZE_FIELD_SIZE:]
+	return
+}
+
+// encode these delivery instructions as tunnel message delivery instructions
+// follow-on fragments are written in the compressed 7 byte form
+func (delivery Delivery) TunnelBytes() (data []byte, err error) {
+	size := make([]byte, SIZE_FIELD_SIZE)
+	binary.BigEndian.PutUint16(size, delivery.Size)
+	message_id := make([]byte, MESSAGE_ID_SIZE)
+	binary.BigEndian.PutUint32(message_id, delivery.MessageID)
+	if delivery.FollowOn {
+		flag := byte(0x80) | byte(delivery.FragmentNumber&0x3f)<<1
+		if delivery.LastFragment {
+			flag |= 0x01
+		}
+		data = append(data, flag)
+		data = append(data, message_id...)
+		data = append(data, size...)
+		return
+	}
+	mode, ok := tunnelModes[delivery.Mode]
+	if !ok {
+		err = ERR_DELIVERY_INVALID_MODE
+		return
+	}
+	flag := mode << 5
+	if delivery.HasDelay {
+		flag |= 0x10
+	}
+	if delivery.Fragmented {
+		flag |= 0x08
+	}
+	if delivery.ExtendedOptions != nil {
+		flag |= 0x04
+	}
+	data = append(data, flag)
+	if delivery.Mode == DELIVERY_TUNNEL {
+		tunnel_id := make([]byte, TUNNEL_ID_SIZE)
+		binary.BigEndian.PutUint32(tunnel_id, uint32(delivery.TunnelID))
+		data = append(data, tunnel_id...)
+	}
+	if delivery.hasHash() {
+		data = append(data, delivery.Hash[:]...)
+	}
+	if delivery.HasDelay {
+		data = append(data, byte(delivery.Delay))
+	}
+	if delivery.Fragmented {
+		data = append(data, message_id...)
+	}
+	if delivery.ExtendedOptions != nil {
+		data = append(data, byte(len(delivery.ExtendedOptions)))
+		data = append(data, delivery.ExtendedOptions...)
+	}
+	data = append(data, size...)
+	return
+}
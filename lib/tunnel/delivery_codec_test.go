@@ -0,0 +1,116 @@
+package tunnel
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func testDeliveryHash() (hash common.Hash) {
+	for i := range hash {
+		hash[i] = byte(i)
+	}
+	return
+}
+
+func TestGarlicDeliveryRoundTripForEachMode(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		delivery Delivery
+		size     int
+	}{
+		{"local", Delivery{Mode: DELIVERY_LOCAL}, 1},
+		{"destination", Delivery{Mode: DELIVERY_DESTINATION, Hash: testDeliveryHash()}, 33},
+		{"router", Delivery{Mode: DELIVERY_ROUTER, Hash: testDeliveryHash()}, 33},
+		{"tunnel", Delivery{Mode: DELIVERY_TUNNEL, Hash: testDeliveryHash(), TunnelID: 1234}, 37},
+		{"delayed", Delivery{Mode: DELIVERY_ROUTER, Hash: testDeliveryHash(), HasDelay: true, Delay: 60}, 37},
+	}
+	for _, test := range tests {
+		data, err := test.delivery.GarlicBytes()
+		assert.Nil(err, test.name)
+		assert.Equal(test.size, len(data), test.name)
+		delivery, remainder, err := ReadGarlicDelivery(append(data, 0xff))
+		assert.Nil(err, test.name)
+		assert.Equal(test.delivery, delivery, test.name)
+		assert.Equal([]byte{0xff}, remainder, test.name)
+	}
+}
+
+func TestGarlicDeliveryRejectsEncrypted(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, err := ReadGarlicDelivery([]byte{0x80})
+	assert.Equal(ERR_DELIVERY_ENCRYPTED_UNSUPPORTED, err)
+}
+
+func TestGarlicDeliveryWithTooLittleData(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, err := ReadGarlicDelivery([]byte{0x60, 0x01, 0x02})
+	assert.Equal(ERR_DELIVERY_NOT_ENOUGH_DATA, err)
+}
+
+func TestTunnelDeliveryRoundTripForEachMode(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		delivery Delivery
+		size     int
+	}{
+		{"local", Delivery{Mode: DELIVERY_LOCAL, Size: 100}, 3},
+		{"router", Delivery{Mode: DELIVERY_ROUTER, Hash: testDeliveryHash(), Size: 100}, 35},
+		{"tunnel", Delivery{Mode: DELIVERY_TUNNEL, Hash: testDeliveryHash(), TunnelID: 1234, Size: 100}, 39},
+		{"router first fragment", Delivery{Mode: DELIVERY_ROUTER, Hash: testDeliveryHash(), Fragmented: true, MessageID: 42, Size: 100}, 39},
+		{"tunnel first fragment", Delivery{Mode: DELIVERY_TUNNEL, Hash: testDeliveryHash(), TunnelID: 1234, Fragmented: true, MessageID: 42, Size: 100}, 43},
+	}
+	for _, test := range tests {
+		data, err := test.delivery.TunnelBytes()
+		assert.Nil(err, test.name)
+		assert.Equal(test.size, len(data), test.name)
+		delivery, remainder, err := ReadTunnelDelivery(append(data, 0xff))
+		assert.Nil(err, test.name)
+		assert.Equal(test.delivery, delivery, test.name)
+		assert.Equal([]byte{0xff}, remainder, test.name)
+	}
+}
+
+func TestTunnelDeliveryFollowOnFragment(t *testing.T) {
+	assert := assert.New(t)
+
+	follow_on := Delivery{
+		FollowOn:       true,
+		Fragmented:     true,
+		FragmentNumber: 5,
+		LastFragment:   true,
+		MessageID:      42,
+		Size:           500,
+	}
+	data, err := follow_on.TunnelBytes()
+	assert.Nil(err)
+	assert.Equal([]byte{0x8b, 0x00, 0x00, 0x00, 0x2a, 0x01, 0xf4}, data)
+	delivery, remainder, err := ReadTunnelDelivery(data)
+	assert.Nil(err)
+	assert.Equal(follow_on, delivery)
+	assert.Equal(0, len(remainder))
+}
+
+func TestTunnelDeliveryRejectsDestinationMode(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := Delivery{Mode: DELIVERY_DESTINATION, Hash: testDeliveryHash()}.TunnelBytes()
+	assert.Equal(ERR_DELIVERY_INVALID_MODE, err)
+	_, _, err = ReadTunnelDelivery([]byte{0x60, 0x00, 0x00})
+	assert.Equal(ERR_DELIVERY_INVALID_MODE, err)
+}
+
+func TestTunnelDeliverySkipsExtendedOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	delivery, remainder, err := ReadTunnelDelivery([]byte{0x04, 0x02, 0xaa, 0xbb, 0x00, 0x10, 0xff})
+	assert.Nil(err)
+	assert.Equal(uint16(16), delivery.Size)
+	assert.Equal([]byte{0xff}, remainder)
+}
@@ -65,3 +65,49 @@ func TestReadDeliveryInstructions(t *testing.T) {
 	)
 	assert.Nil(err)
 }
+
+func TestDeliveryInstructionsAccessorsMatchCodec(t *testing.T) {
+	assert := assert.New(t)
+
+	first := Delivery{
+		Mode:            DELIVERY_TUNNEL,
+		Hash:            common.Hash{0x01},
+		TunnelID:        7,
+		Fragmented:      true,
+		MessageID:       42,
+		Size:            3,
+		ExtendedOptions: []byte{0xaa},
+	}
+	data, err := first.TunnelBytes()
+	assert.Nil(err)
+	instructions, remainder, err := readDeliveryInstructions(append(data, 0x01, 0x02, 0x03))
+	assert.Nil(err)
+	assert.Equal([]byte{0x01, 0x02, 0x03}, remainder)
+
+	di_type, _ := instructions.Type()
+	assert.Equal(FIRST_FRAGMENT, di_type)
+	delivery_type, _ := instructions.DeliveryType()
+	assert.Equal(byte(DT_TUNNEL), delivery_type)
+	tunnel_id, _ := instructions.TunnelID()
+	assert.Equal(uint32(7), tunnel_id)
+	hash, _ := instructions.Hash()
+	assert.Equal(first.Hash, hash)
+	message_id, _ := instructions.MessageID()
+	assert.Equal(uint32(42), message_id)
+	options, _ := instructions.ExtendedOptions()
+	assert.Equal([]byte{0xaa}, options)
+	size, _ := instructions.FragmentSize()
+	assert.Equal(uint16(3), size)
+
+	follow_on := Delivery{FollowOn: true, Fragmented: true, FragmentNumber: 2, LastFragment: true, MessageID: 42, Size: 9}
+	data, _ = follow_on.TunnelBytes()
+	instructions = DeliveryInstructions(data)
+	di_type, _ = instructions.Type()
+	assert.Equal(FOLLOW_ON_FRAGMENT, di_type)
+	number, _ := instructions.FragmentNumber()
+	assert.Equal(2, number)
+	last, _ := instructions.LastFollowOnFragment()
+	assert.True(last)
+	_, err = instructions.DeliveryType()
+	assert.NotNil(err)
+}
@@ -0,0 +1,82 @@
+package tunnel
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	log "github.com/sirupsen/logrus"
+	"net"
+)
+
+// Prefix lengths treated as one network when choosing diverse tunnel hops
+const (
+	HOP_SUBNET_IPV4_PREFIX = 16
+	HOP_SUBNET_IPV6_PREFIX = 64
+)
+
+//
+// Return the subnet an IP belongs to for hop diversity, the /16 for IPv4 and the
+// /64 for IPv6, or an empty string if the IP is invalid.
+//
+func HopSubnet(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(HOP_SUBNET_IPV4_PREFIX, 32)).String() + "/16"
+	}
+	if ip6 := ip.To16(); ip6 != nil {
+		return ip6.Mask(net.CIDRMask(HOP_SUBNET_IPV6_PREFIX, 128)).String() + "/64"
+	}
+	return ""
+}
+
+//
+// Return the subnets of every published host address in a RouterInfo.
+//
+func routerInfoSubnets(router_info common.RouterInfo) (subnets []string) {
+	addresses, _ := router_info.RouterAddresses()
+	for _, address := range addresses {
+		options, err := address.Options()
+		if err != nil || len(options) == 0 {
+			continue
+		}
+		values, _ := options.Values()
+		host := values.Get("host")
+		if host == nil {
+			continue
+		}
+		host_str, _ := host.Data()
+		if subnet := HopSubnet(net.ParseIP(host_str)); subnet != "" {
+			subnets = append(subnets, subnet)
+		}
+	}
+	return
+}
+
+//
+// Return the candidate hops, in order, skipping any that share an IPv4 /16 or an
+// IPv6 /64 with a hop already chosen so a tunnel spans distinct networks.
+// Candidates that publish no address are not restricted.
+//
+func DiverseHops(candidates []common.RouterInfo) (hops []common.RouterInfo) {
+	used := make(map[string]bool)
+	for _, router_info := range candidates {
+		subnets := routerInfoSubnets(router_info)
+		taken := false
+		for _, subnet := range subnets {
+			if used[subnet] {
+				taken = true
+				break
+			}
+		}
+		if taken {
+			log.WithFields(log.Fields{
+				"at":      "tunnel.DiverseHops",
+				"subnets": subnets,
+				"reason":  "subnet already used by another hop",
+			}).Debug("skipping hop")
+			continue
+		}
+		for _, subnet := range subnets {
+			used[subnet] = true
+		}
+		hops = append(hops, router_info)
+	}
+	return
+}
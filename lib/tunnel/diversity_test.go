@@ -0,0 +1,55 @@
+package tunnel
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/go-i2p/go-i2p/lib/internal/fixture"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+)
+
+func buildAddressedRouterInfo(seed byte, hosts ...string) common.RouterInfo {
+	spec := fixture.RouterInfo{Seed: seed}
+	for _, host := range hosts {
+		spec.Addresses = append(spec.Addresses, fixture.Address(0, "NTCP2", map[string]string{"host": host, "port": "4567"}))
+	}
+	return spec.Build()
+}
+
+func TestHopSubnet(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("10.1.0.0/16", HopSubnet(net.ParseIP("10.1.2.3")))
+	assert.Equal("2001:db8:1:2::/64", HopSubnet(net.ParseIP("2001:db8:1:2:aaaa::1")))
+	assert.Equal("", HopSubnet(nil))
+}
+
+func TestDiverseHopsSkipsSameIPv6Slash64(t *testing.T) {
+	assert := assert.New(t)
+
+	first := buildAddressedRouterInfo(0x01, "2001:db8:1:2::1")
+	same := buildAddressedRouterInfo(0x02, "2001:db8:1:2:ffff::2")
+	other := buildAddressedRouterInfo(0x03, "2001:db8:1:3::1")
+	hops := DiverseHops([]common.RouterInfo{first, same, other})
+	assert.Equal([]common.RouterInfo{first, other}, hops)
+}
+
+func TestDiverseHopsSkipsSameIPv4Slash16(t *testing.T) {
+	assert := assert.New(t)
+
+	first := buildAddressedRouterInfo(0x01, "192.168.1.1")
+	same := buildAddressedRouterInfo(0x02, "192.168.200.7")
+	other := buildAddressedRouterInfo(0x03, "192.169.1.1")
+	hops := DiverseHops([]common.RouterInfo{first, same, other})
+	assert.Equal([]common.RouterInfo{first, other}, hops)
+}
+
+func TestDiverseHopsDualStack(t *testing.T) {
+	assert := assert.New(t)
+
+	dual := buildAddressedRouterInfo(0x01, "192.168.1.1", "2001:db8:1:2::1")
+	v6 := buildAddressedRouterInfo(0x02, "2001:db8:1:2::9")
+	v4 := buildAddressedRouterInfo(0x03, "10.0.0.1")
+	hops := DiverseHops([]common.RouterInfo{dual, v6, v4})
+	assert.Equal([]common.RouterInfo{dual, v4}, hops)
+}
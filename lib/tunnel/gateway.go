@@ -1 +1,225 @@
 package tunnel
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/crypto"
+	log "github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// room for delivery instructions and fragments in one tunnel message after the
+// tunnel ID, IV, checksum and the zero byte ending the padding
+const TUNNEL_MESSAGE_DATA_SIZE = 1028 - 4 - 16 - 4 - 1
+
+// size of the delivery instructions of a follow-on fragment
+const FOLLOW_ON_DELIVERY_SIZE = FLAG_SIZE + MESSAGE_ID_SIZE + SIZE_FIELD_SIZE
+
+// how long an outbound gateway waits for more messages before sending a partly filled
+// tunnel message by default
+const DEFAULT_GATEWAY_FLUSH_DELAY = 100 * time.Millisecond
+
+var ERR_GATEWAY_MESSAGE_TOO_LARGE = errors.New("message is too large to fragment into one tunnel message stream")
+
+//
+// An outbound tunnel gateway that batches I2NP messages into as few tunnel messages
+// as possible.  Small messages are coalesced until a tunnel message is full or
+// FlushDelay passes after the first one was added; a FlushDelay of 0 sends every
+// message at once, trading throughput for latency.  Messages too large for one
+// tunnel message are fragmented.
+//
+type Gateway struct {
+	TunnelID   TunnelID
+	FlushDelay time.Duration
+	// called with each completed tunnel message, before it is encrypted for the hops
+	Send    func(DecryptedTunnelMessage)
+	access  sync.Mutex
+	pending []byte
+	// built tunnel messages waiting to be passed to Send once the lock is released
+	ready []DecryptedTunnelMessage
+	timer *time.Timer
+}
+
+//
+// Create a Gateway for the tunnel whose first hop receives on tunnel_id.
+//
+func NewGateway(tunnel_id TunnelID, flush_delay time.Duration, send func(DecryptedTunnelMessage)) (gateway *Gateway) {
+	gateway = &Gateway{
+		TunnelID:   tunnel_id,
+		FlushDelay: flush_delay,
+		Send:       send,
+	}
+	return
+}
+
+//
+// Queue an I2NP message for delivery according to delivery.  Only the Mode, Hash
+// and TunnelID of delivery are used.
+//
+func (gateway *Gateway) Add(delivery Delivery, msg []byte) (err error) {
+	if len(msg) > 0xffff {
+		err = ERR_GATEWAY_MESSAGE_TOO_LARGE
+		return
+	}
+	delivery = Delivery{
+		Mode:     delivery.Mode,
+		Hash:     delivery.Hash,
+		TunnelID: delivery.TunnelID,
+		Size:     uint16(len(msg)),
+	}
+	instructions, err := delivery.TunnelBytes()
+	if err != nil {
+		return
+	}
+	err = gateway.add(delivery, instructions, msg)
+	gateway.send()
+	return
+}
+
+// queue msg behind its delivery instructions, fragmenting it if it does not fit in one
+// tunnel message
+func (gateway *Gateway) add(delivery Delivery, instructions, msg []byte) (err error) {
+	gateway.access.Lock()
+	defer gateway.access.Unlock()
+	whole := len(instructions) + len(msg)
+	if whole <= TUNNEL_MESSAGE_DATA_SIZE {
+		if len(gateway.pending)+whole > TUNNEL_MESSAGE_DATA_SIZE {
+			gateway.flush()
+		}
+		gateway.append(instructions, msg)
+	} else {
+		err = gateway.fragment(delivery, msg)
+		if err != nil {
+			return
+		}
+	}
+	if gateway.FlushDelay <= 0 {
+		gateway.flush()
+	} else if len(gateway.pending) > 0 && gateway.timer == nil {
+		gateway.timer = time.AfterFunc(gateway.FlushDelay, gateway.Flush)
+	}
+	return
+}
+
+//
+// Send whatever is queued now instead of waiting for FlushDelay.
+//
+func (gateway *Gateway) Flush() {
+	gateway.access.Lock()
+	gateway.flush()
+	gateway.access.Unlock()
+	gateway.send()
+}
+
+// pass the built tunnel messages to Send, the lock must not be held so that Send may
+// queue more messages into this gateway
+func (gateway *Gateway) send() {
+	gateway.access.Lock()
+	ready := gateway.ready
+	gateway.ready = nil
+	gateway.access.Unlock()
+	for _, msg := range ready {
+		gateway.Send(msg)
+	}
+}
+
+// split msg into a first fragment filling the current tunnel message and follow-on
+// fragments filling the ones after it, the lock must be held
+func (gateway *Gateway) fragment(delivery Delivery, msg []byte) (err error) {
+	message_id := make([]byte, MESSAGE_ID_SIZE)
+	if _, err = rand.Read(message_id); err != nil {
+		return
+	}
+	delivery.Fragmented = true
+	delivery.MessageID = binary.BigEndian.Uint32(message_id)
+	for fragment_number := 0; len(msg) > 0; fragment_number++ {
+		if fragment_number > 0 {
+			delivery.FollowOn = true
+			delivery.FragmentNumber = fragment_number
+		}
+		if fragment_number > 0x3f {
+			err = ERR_GATEWAY_MESSAGE_TOO_LARGE
+			return
+		}
+		header_size := FOLLOW_ON_DELIVERY_SIZE
+		if !delivery.FollowOn {
+			delivery.Size = 0
+			header, _ := delivery.TunnelBytes()
+			header_size = len(header)
+		}
+		if TUNNEL_MESSAGE_DATA_SIZE-len(gateway.pending) <= header_size {
+			gateway.flush()
+		}
+		chunk := TUNNEL_MESSAGE_DATA_SIZE - len(gateway.pending) - header_size
+		if chunk > len(msg) {
+			chunk = len(msg)
+		}
+		delivery.Size = uint16(chunk)
+		delivery.LastFragment = chunk == len(msg)
+		instructions, _ := delivery.TunnelBytes()
+		gateway.append(instructions, msg[:chunk])
+		msg = msg[chunk:]
+	}
+	return
+}
+
+// the lock must be held
+func (gateway *Gateway) append(instructions, fragment []byte) {
+	gateway.pending = append(gateway.pending, instructions...)
+	gateway.pending = append(gateway.pending, fragment...)
+	if len(gateway.pending) == TUNNEL_MESSAGE_DATA_SIZE {
+		gateway.flush()
+	}
+}
+
+// build the pending tunnel message if there is one and queue it for send, the lock must
+// be held
+func (gateway *Gateway) flush() {
+	if gateway.timer != nil {
+		gateway.timer.Stop()
+		gateway.timer = nil
+	}
+	if len(gateway.pending) == 0 {
+		return
+	}
+	msg, err := buildTunnelMessage(gateway.TunnelID, gateway.pending)
+	gateway.pending = nil
+	if err != nil {
+		log.WithFields(log.Fields{
+			"at":     "(Gateway) flush",
+			"reason": err.Error(),
+		}).Error("failed to build tunnel message")
+		return
+	}
+	gateway.ready = append(gateway.ready, msg)
+}
+
+// lay out a decrypted tunnel message carrying data with a random IV and nonzero padding
+func buildTunnelMessage(tunnel_id TunnelID, data []byte) (msg DecryptedTunnelMessage, err error) {
+	binary.BigEndian.PutUint32(msg[:4], uint32(tunnel_id))
+	iv := msg[4 : 4+16]
+	if _, err = rand.Read(iv); err != nil {
+		return
+	}
+	start := len(msg) - len(data)
+	copy(msg[start:], data)
+	msg[start-1] = 0x00
+	padding := msg[4+16+4 : start-1]
+	if _, err = rand.Read(padding); err != nil {
+		return
+	}
+	for i := range padding {
+		for padding[i] == 0x00 {
+			b := make([]byte, 1)
+			if _, err = rand.Read(b); err != nil {
+				return
+			}
+			padding[i] = b[0]
+		}
+	}
+	checksum := crypto.SHA256(append(append([]byte{}, data...), iv...))
+	copy(msg[4+16:4+16+4], checksum[:4])
+	return
+}
@@ -0,0 +1,177 @@
+package tunnel
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type gatewayFragment struct {
+	delivery Delivery
+	data     []byte
+}
+
+// read back every delivery instruction and fragment from a tunnel message built by a Gateway
+func readGatewayMessage(t *testing.T, msg DecryptedTunnelMessage) (fragments []gatewayFragment) {
+	data := msg.deliveryInstructionData()
+	checksum := sha256.Sum256(append(append([]byte{}, data...), msg.IV()...))
+	assert.Equal(t, []byte(msg.Checksum()), checksum[:4], "checksum must cover the data and IV")
+	for len(data) > 0 {
+		delivery, remainder, err := ReadTunnelDelivery(data)
+		if !assert.Nil(t, err) {
+			return
+		}
+		fragments = append(fragments, gatewayFragment{delivery, remainder[:delivery.Size]})
+		data = remainder[delivery.Size:]
+	}
+	return
+}
+
+func TestGatewayBatchesSmallMessages(t *testing.T) {
+	assert := assert.New(t)
+
+	sent := []DecryptedTunnelMessage{}
+	gateway := NewGateway(TunnelID(1234), time.Hour, func(msg DecryptedTunnelMessage) {
+		sent = append(sent, msg)
+	})
+	messages := [][]byte{
+		bytes.Repeat([]byte{0x01}, 100),
+		bytes.Repeat([]byte{0x02}, 200),
+		bytes.Repeat([]byte{0x03}, 50),
+	}
+	for _, msg := range messages {
+		assert.Nil(gateway.Add(Delivery{Mode: DELIVERY_LOCAL}, msg))
+	}
+	assert.Empty(sent, "messages should wait for the flush delay")
+	gateway.Flush()
+
+	if assert.Equal(1, len(sent), "small messages should share one tunnel message") {
+		assert.Equal(TunnelID(1234), sent[0].ID())
+		fragments := readGatewayMessage(t, sent[0])
+		if assert.Equal(3, len(fragments)) {
+			for i, fragment := range fragments {
+				assert.Equal(DELIVERY_LOCAL, fragment.delivery.Mode)
+				assert.False(fragment.delivery.Fragmented)
+				assert.Equal(messages[i], fragment.data)
+			}
+		}
+	}
+}
+
+func TestGatewayStartsNewMessageWhenFull(t *testing.T) {
+	assert := assert.New(t)
+
+	sent := []DecryptedTunnelMessage{}
+	gateway := NewGateway(TunnelID(1), time.Hour, func(msg DecryptedTunnelMessage) {
+		sent = append(sent, msg)
+	})
+	gateway.Add(Delivery{Mode: DELIVERY_LOCAL}, make([]byte, 600))
+	gateway.Add(Delivery{Mode: DELIVERY_LOCAL}, make([]byte, 600))
+	assert.Equal(1, len(sent), "second message does not fit with the first")
+	gateway.Flush()
+	assert.Equal(2, len(sent))
+}
+
+func TestGatewayFragmentsLargeMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	sent := []DecryptedTunnelMessage{}
+	gateway := NewGateway(TunnelID(1), time.Hour, func(msg DecryptedTunnelMessage) {
+		sent = append(sent, msg)
+	})
+	msg := make([]byte, 2500)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+	assert.Nil(gateway.Add(Delivery{Mode: DELIVERY_ROUTER, Hash: [32]byte{0x01}}, msg))
+	gateway.Flush()
+
+	assert.Equal(3, len(sent))
+	reassembled := []byte{}
+	for i, tunnel_msg := range sent {
+		fragments := readGatewayMessage(t, tunnel_msg)
+		if !assert.Equal(1, len(fragments)) {
+			return
+		}
+		delivery := fragments[0].delivery
+		assert.True(delivery.Fragmented)
+		assert.Equal(i, delivery.FragmentNumber)
+		assert.Equal(i > 0, delivery.FollowOn)
+		assert.Equal(i == len(sent)-1, delivery.LastFragment)
+		reassembled = append(reassembled, fragments[0].data...)
+	}
+	assert.Equal(msg, reassembled)
+}
+
+func TestGatewayFlushesAfterDelay(t *testing.T) {
+	assert := assert.New(t)
+
+	sent := make(chan DecryptedTunnelMessage, 1)
+	gateway := NewGateway(TunnelID(1), 10*time.Millisecond, func(msg DecryptedTunnelMessage) {
+		sent <- msg
+	})
+	gateway.Add(Delivery{Mode: DELIVERY_LOCAL}, []byte{0x01})
+	select {
+	case msg := <-sent:
+		assert.Equal(1, len(readGatewayMessage(t, msg)))
+	case <-time.After(time.Second):
+		t.Fatal("gateway did not flush after its delay")
+	}
+}
+
+func TestGatewayWithoutDelaySendsImmediately(t *testing.T) {
+	assert := assert.New(t)
+
+	sent := 0
+	gateway := NewGateway(TunnelID(1), 0, func(DecryptedTunnelMessage) { sent++ })
+	gateway.Add(Delivery{Mode: DELIVERY_LOCAL}, []byte{0x01})
+	gateway.Add(Delivery{Mode: DELIVERY_LOCAL}, []byte{0x02})
+	assert.Equal(2, sent)
+}
+
+func TestGatewaySendMayQueueIntoSameGateway(t *testing.T) {
+	assert := assert.New(t)
+
+	sent := make(chan DecryptedTunnelMessage, 2)
+	requeued := false
+	var gateway *Gateway
+	gateway = NewGateway(TunnelID(1), 0, func(msg DecryptedTunnelMessage) {
+		if !requeued {
+			requeued = true
+			assert.Nil(gateway.Add(Delivery{Mode: DELIVERY_LOCAL}, []byte{0x02}))
+		}
+		sent <- msg
+	})
+	done := make(chan error, 1)
+	go func() { done <- gateway.Add(Delivery{Mode: DELIVERY_LOCAL}, []byte{0x01}) }()
+	select {
+	case err := <-done:
+		assert.Nil(err)
+		assert.Equal(2, len(sent))
+	case <-time.After(time.Second):
+		t.Fatal("Send queueing into its own gateway deadlocked")
+	}
+}
+
+func TestGatewayDelayedSendMayQueueIntoSameGateway(t *testing.T) {
+	sent := make(chan DecryptedTunnelMessage, 2)
+	requeued := false
+	var gateway *Gateway
+	gateway = NewGateway(TunnelID(1), 10*time.Millisecond, func(msg DecryptedTunnelMessage) {
+		if !requeued {
+			requeued = true
+			gateway.Add(Delivery{Mode: DELIVERY_LOCAL}, []byte{0x02})
+		}
+		sent <- msg
+	})
+	gateway.Add(Delivery{Mode: DELIVERY_LOCAL}, []byte{0x01})
+	for i := 0; i < 2; i++ {
+		select {
+		case <-sent:
+		case <-time.After(time.Second):
+			t.Fatal("delayed Send queueing into its own gateway deadlocked")
+		}
+	}
+}
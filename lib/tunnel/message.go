@@ -175,7 +175,7 @@ func (decrypted_tunnel_message DecryptedTunnelMessage) deliveryInstructionData()
 func (decrypted_tunnel_message DecryptedTunnelMessage) DeliveryInstructionsWithFragments() []DeliveryInstructionsWithFragment {
 	set := make([]DeliveryInstructionsWithFragment, 0)
 	data := decrypted_tunnel_message.deliveryInstructionData()
-	for {
+	for len(data) > 0 {
 		instructions, remainder, err := readDeliveryInstructions(data)
 		if err != nil {
 			log.WithFields(log.Fields{
@@ -194,6 +194,14 @@ func (decrypted_tunnel_message DecryptedTunnelMessage) DeliveryInstructionsWithF
 			break
 		}
 
+		if int(fragment_size) > len(remainder) {
+			log.WithFields(log.Fields{
+				"at":            "(DecryptedTunnelMessage) DeliveryInstructionsWithFragments",
+				"fragment_size": fragment_size,
+				"data_len":      len(remainder),
+			}).Error("delivery instructions fragment size exceeds message")
+			break
+		}
 		fragment_data := remainder[:fragment_size]
 		pair := DeliveryInstructionsWithFragment{
 			DeliveryInstructions: instructions,
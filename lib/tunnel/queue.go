@@ -0,0 +1,144 @@
+package tunnel
+
+import (
+	log "github.com/sirupsen/logrus"
+	"sync"
+)
+
+// what a MessageQueue does with a message pushed while it is full
+type QueuePolicy int
+
+const (
+	// block the sender until there is room
+	QUEUE_BLOCK QueuePolicy = iota
+	// drop the message being pushed
+	QUEUE_DROP_NEWEST
+	// drop the oldest queued message to make room
+	QUEUE_DROP_OLDEST
+)
+
+//
+// A bounded queue of tunnel messages waiting to be sent down an outbound tunnel.
+// At most capacity messages are held, so a slow first hop cannot make the router
+// buffer without limit; what happens when it is full is set by its QueuePolicy.
+//
+type MessageQueue struct {
+	access   sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	messages []EncryptedTunnelMessage
+	head     int
+	count    int
+	policy   QueuePolicy
+	drops    uint64
+	closed   bool
+}
+
+//
+// Create a MessageQueue holding at most capacity messages with the given policy.
+//
+func NewMessageQueue(capacity int, policy QueuePolicy) (queue *MessageQueue) {
+	if capacity < 1 {
+		capacity = 1
+	}
+	queue = &MessageQueue{
+		messages: make([]EncryptedTunnelMessage, capacity),
+		policy:   policy,
+	}
+	queue.notEmpty = sync.NewCond(&queue.access)
+	queue.notFull = sync.NewCond(&queue.access)
+	return
+}
+
+//
+// Queue a message, applying the QueuePolicy if the queue is full.  Returns false
+// if the message was dropped or the queue is closed.
+//
+func (queue *MessageQueue) Push(msg EncryptedTunnelMessage) (queued bool) {
+	queue.access.Lock()
+	defer queue.access.Unlock()
+	for queue.count == len(queue.messages) && !queue.closed {
+		switch queue.policy {
+		case QUEUE_DROP_NEWEST:
+			queue.dropped()
+			return false
+		case QUEUE_DROP_OLDEST:
+			queue.messages[queue.head] = EncryptedTunnelMessage{}
+			queue.head = (queue.head + 1) % len(queue.messages)
+			queue.count--
+			queue.dropped()
+		default:
+			queue.notFull.Wait()
+		}
+	}
+	if queue.closed {
+		return false
+	}
+	queue.messages[(queue.head+queue.count)%len(queue.messages)] = msg
+	queue.count++
+	queue.notEmpty.Signal()
+	return true
+}
+
+//
+// Take the oldest message off the queue, blocking until there is one.  Returns
+// false once the queue is closed and empty.
+//
+func (queue *MessageQueue) Pop() (msg EncryptedTunnelMessage, ok bool) {
+	queue.access.Lock()
+	defer queue.access.Unlock()
+	for queue.count == 0 && !queue.closed {
+		queue.notEmpty.Wait()
+	}
+	if queue.count == 0 {
+		return
+	}
+	msg = queue.messages[queue.head]
+	// clear the slot so popped messages are not kept until it is reused
+	queue.messages[queue.head] = EncryptedTunnelMessage{}
+	queue.head = (queue.head + 1) % len(queue.messages)
+	queue.count--
+	queue.notFull.Signal()
+	return msg, true
+}
+
+//
+// Return how many messages are queued.
+//
+func (queue *MessageQueue) Len() int {
+	queue.access.Lock()
+	defer queue.access.Unlock()
+	return queue.count
+}
+
+//
+// Return how many messages have been dropped because the queue was full.
+//
+func (queue *MessageQueue) Drops() uint64 {
+	queue.access.Lock()
+	defer queue.access.Unlock()
+	return queue.drops
+}
+
+//
+// Close the queue, waking any blocked senders and receivers.  Queued messages
+// can still be popped.
+//
+func (queue *MessageQueue) Close() {
+	queue.access.Lock()
+	queue.closed = true
+	queue.access.Unlock()
+	queue.notEmpty.Broadcast()
+	queue.notFull.Broadcast()
+}
+
+// count a dropped message, the lock must be held
+func (queue *MessageQueue) dropped() {
+	queue.drops++
+	log.WithFields(log.Fields{
+		"at":     "(MessageQueue) Push",
+		"drops":  queue.drops,
+		"policy": queue.policy,
+		"reason": "queue full",
+	}).Debug("dropped tunnel message")
+}
@@ -0,0 +1,101 @@
+package tunnel
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func queueTestMessage(n byte) (msg EncryptedTunnelMessage) {
+	msg[0] = n
+	return
+}
+
+func drainQueue(queue *MessageQueue) (firsts []byte) {
+	queue.Close()
+	for {
+		msg, ok := queue.Pop()
+		if !ok {
+			return
+		}
+		firsts = append(firsts, msg[0])
+	}
+}
+
+func TestMessageQueueDropOldestUnderOverload(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewMessageQueue(4, QUEUE_DROP_OLDEST)
+	for i := 0; i < 10; i++ {
+		assert.True(queue.Push(queueTestMessage(byte(i))))
+	}
+	assert.Equal(4, queue.Len())
+	assert.Equal(uint64(6), queue.Drops())
+	assert.Equal([]byte{6, 7, 8, 9}, drainQueue(queue))
+}
+
+func TestMessageQueueDropNewestUnderOverload(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewMessageQueue(4, QUEUE_DROP_NEWEST)
+	for i := 0; i < 10; i++ {
+		queue.Push(queueTestMessage(byte(i)))
+	}
+	assert.Equal(4, queue.Len())
+	assert.Equal(uint64(6), queue.Drops())
+	assert.Equal([]byte{0, 1, 2, 3}, drainQueue(queue))
+}
+
+func TestMessageQueueClearsPoppedAndDroppedSlots(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewMessageQueue(2, QUEUE_DROP_OLDEST)
+	for i := 1; i <= 3; i++ {
+		queue.Push(queueTestMessage(byte(i)))
+	}
+	msg, ok := queue.Pop()
+	assert.True(ok)
+	assert.Equal(byte(2), msg[0])
+	assert.Equal(1, queue.Len())
+	for idx, slot := range queue.messages {
+		if slot[0] != 3 {
+			assert.Equal(EncryptedTunnelMessage{}, slot, "slot %d still holds a message", idx)
+		}
+	}
+}
+
+func TestMessageQueueBlocksWhenFull(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewMessageQueue(1, QUEUE_BLOCK)
+	assert.True(queue.Push(queueTestMessage(1)))
+	pushed := make(chan bool)
+	go func() {
+		pushed <- queue.Push(queueTestMessage(2))
+	}()
+	select {
+	case <-pushed:
+		t.Fatal("push into a full blocking queue did not block")
+	case <-time.After(50 * time.Millisecond):
+	}
+	msg, ok := queue.Pop()
+	assert.True(ok)
+	assert.Equal(byte(1), msg[0])
+	assert.True(<-pushed)
+	assert.Equal(uint64(0), queue.Drops())
+}
+
+func TestMessageQueueCloseUnblocksSender(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := NewMessageQueue(1, QUEUE_BLOCK)
+	queue.Push(queueTestMessage(1))
+	pushed := make(chan bool)
+	go func() {
+		pushed <- queue.Push(queueTestMessage(2))
+	}()
+	time.Sleep(10 * time.Millisecond)
+	queue.Close()
+	assert.False(<-pushed)
+	assert.Equal([]byte{1}, drainQueue(queue))
+}
@@ -0,0 +1,56 @@
+package tunnel
+
+import (
+	"errors"
+	"github.com/go-i2p/go-i2p/lib/common"
+	log "github.com/sirupsen/logrus"
+)
+
+var ERR_NOT_ENOUGH_HOPS = errors.New("peer selector returned too few hops for tunnel")
+
+//
+// A PeerSelector chooses which routers to use as the hops of a new tunnel, so the
+// selection policy can be swapped out and tested on its own.
+//
+type PeerSelector interface {
+	// Return up to count hops chosen from candidates, in tunnel order.
+	SelectPeers(candidates []common.RouterInfo, count int) []common.RouterInfo
+}
+
+//
+// The default PeerSelector, which keeps the candidates in the order given and skips
+// any that would put two hops in the same subnet, see DiverseHops.
+//
+type DiversePeerSelector struct{}
+
+func (selector DiversePeerSelector) SelectPeers(candidates []common.RouterInfo, count int) []common.RouterInfo {
+	hops := DiverseHops(candidates)
+	if len(hops) > count {
+		hops = hops[:count]
+	}
+	return hops
+}
+
+//
+// Choose exactly length hops from candidates using selector, or DiversePeerSelector
+// if selector is nil.  Returns ERR_NOT_ENOUGH_HOPS if the selector could not find
+// enough hops or returned more than were asked for.
+//
+func SelectTunnelHops(selector PeerSelector, candidates []common.RouterInfo, length int) (hops []common.RouterInfo, err error) {
+	if selector == nil {
+		selector = DiversePeerSelector{}
+	}
+	hops = selector.SelectPeers(candidates, length)
+	if len(hops) != length {
+		log.WithFields(log.Fields{
+			"at":         "tunnel.SelectTunnelHops",
+			"candidates": len(candidates),
+			"selected":   len(hops),
+			"length":     length,
+			"reason":     "selector returned wrong number of hops",
+		}).Warn("unable to select tunnel hops")
+		hops = nil
+		err = ERR_NOT_ENOUGH_HOPS
+	}
+	return
+}
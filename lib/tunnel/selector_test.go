@@ -0,0 +1,49 @@
+package tunnel
+
+import (
+	"github.com/go-i2p/go-i2p/lib/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// picks the last count candidates, last first
+type reversePeerSelector struct{}
+
+func (selector reversePeerSelector) SelectPeers(candidates []common.RouterInfo, count int) (hops []common.RouterInfo) {
+	for i := len(candidates) - 1; i >= 0 && len(hops) < count; i-- {
+		hops = append(hops, candidates[i])
+	}
+	return
+}
+
+func TestSelectTunnelHopsUsesSelector(t *testing.T) {
+	assert := assert.New(t)
+
+	a := buildAddressedRouterInfo(0x01, "10.1.0.1")
+	b := buildAddressedRouterInfo(0x02, "10.1.0.2")
+	c := buildAddressedRouterInfo(0x03, "10.2.0.1")
+	hops, err := SelectTunnelHops(reversePeerSelector{}, []common.RouterInfo{a, b, c}, 2)
+	assert.Nil(err)
+	assert.Equal([]common.RouterInfo{c, b}, hops)
+}
+
+func TestSelectTunnelHopsDefaultsToDiverseSelector(t *testing.T) {
+	assert := assert.New(t)
+
+	a := buildAddressedRouterInfo(0x01, "10.1.0.1")
+	b := buildAddressedRouterInfo(0x02, "10.1.0.2")
+	c := buildAddressedRouterInfo(0x03, "10.2.0.1")
+	hops, err := SelectTunnelHops(nil, []common.RouterInfo{a, b, c}, 2)
+	assert.Nil(err)
+	assert.Equal([]common.RouterInfo{a, c}, hops)
+}
+
+func TestSelectTunnelHopsWithTooFewCandidates(t *testing.T) {
+	assert := assert.New(t)
+
+	a := buildAddressedRouterInfo(0x01, "10.1.0.1")
+	b := buildAddressedRouterInfo(0x02, "10.1.0.2")
+	hops, err := SelectTunnelHops(DiversePeerSelector{}, []common.RouterInfo{a, b}, 2)
+	assert.Equal(ERR_NOT_ENOUGH_HOPS, err)
+	assert.Nil(hops)
+}
@@ -0,0 +1,31 @@
+package util
+
+import (
+	"time"
+)
+
+// a source of the current time so that expiry checks can be pinned in tests
+// and follow a skew adjusted clock in the router
+type Clock interface {
+	Now() time.Time
+}
+
+// the local system clock
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// a Clock running Offset ahead of another Clock
+// i.e. our clock corrected by the skew measured against peers
+type OffsetClock struct {
+	Clock  Clock
+	Offset time.Duration
+}
+
+func (clock OffsetClock) Now() time.Time {
+	return clock.Clock.Now().Add(clock.Offset)
+}
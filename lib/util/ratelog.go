@@ -0,0 +1,115 @@
+package util
+
+import (
+	log "github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// how often repeated identical warnings are summarized by default
+const DEFAULT_RATE_LIMIT_INTERVAL = time.Minute
+
+// a warning we have logged and how many repeats of it we have held back since
+type rateLimitedWarning struct {
+	msg        string
+	fields     log.Fields
+	first      time.Time
+	suppressed int
+}
+
+// RateLimitedLog collapses repeated identical warnings, which a flood of malformed
+// input would otherwise turn into a flood of log lines.  The first warning for a
+// message is logged, repeats within Interval are counted, and the count is logged as
+// a single summary once the interval has passed.
+// warnings are identical if they have the same message and the same "at" and
+// "reason" fields, other fields such as lengths may differ
+type RateLimitedLog struct {
+	Interval time.Duration
+	Logger   *log.Logger
+	// returns the current time, for tests
+	now      func() time.Time
+	access   sync.Mutex
+	warnings map[string]*rateLimitedWarning
+}
+
+// create a RateLimitedLog writing to the standard logrus logger
+func NewRateLimitedLog(interval time.Duration) (rl *RateLimitedLog) {
+	rl = &RateLimitedLog{
+		Interval: interval,
+		Logger:   log.StandardLogger(),
+		now:      time.Now,
+		warnings: make(map[string]*rateLimitedWarning),
+	}
+	return
+}
+
+func rateLimitKey(fields log.Fields, msg string) string {
+	key := msg
+	for _, name := range []string{"at", "reason"} {
+		if value, ok := fields[name].(string); ok {
+			key += "|" + value
+		}
+	}
+	return key
+}
+
+// log a warning unless an identical one was logged less than Interval ago
+func (rl *RateLimitedLog) Warn(fields log.Fields, msg string) {
+	key := rateLimitKey(fields, msg)
+	now := rl.now()
+	rl.access.Lock()
+	defer rl.access.Unlock()
+	if warning, ok := rl.warnings[key]; ok {
+		if now.Sub(warning.first) < rl.Interval {
+			warning.suppressed++
+			return
+		}
+		rl.summarize(warning, now)
+	}
+	rl.warnings[key] = &rateLimitedWarning{msg: msg, fields: fields, first: now}
+	rl.Logger.WithFields(fields).Warn(msg)
+}
+
+// log summaries for every warning that has had repeats held back
+func (rl *RateLimitedLog) Flush() {
+	now := rl.now()
+	rl.access.Lock()
+	defer rl.access.Unlock()
+	for key, warning := range rl.warnings {
+		rl.summarize(warning, now)
+		delete(rl.warnings, key)
+	}
+}
+
+// log summaries for warnings first seen at least Interval before now and forget them,
+// so a burst that has ended is still summarized
+// returns how many warnings were forgotten, for use with a Reaper
+func (rl *RateLimitedLog) Expire(now time.Time) (count int) {
+	rl.access.Lock()
+	defer rl.access.Unlock()
+	for key, warning := range rl.warnings {
+		if now.Sub(warning.first) >= rl.Interval {
+			rl.summarize(warning, now)
+			delete(rl.warnings, key)
+			count++
+		}
+	}
+	return
+}
+
+func (rl *RateLimitedLog) summarize(warning *rateLimitedWarning, now time.Time) {
+	if warning.suppressed == 0 {
+		return
+	}
+	fields := log.Fields{
+		"suppressed": warning.suppressed,
+		"period":     now.Sub(warning.first).String(),
+	}
+	for _, name := range []string{"at", "reason"} {
+		if value, ok := warning.fields[name]; ok {
+			fields[name] = value
+		}
+	}
+	rl.Logger.WithFields(fields).Warnf("%d more %q warnings suppressed", warning.suppressed, warning.msg)
+	warning.suppressed = 0
+}
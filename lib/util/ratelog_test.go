@@ -0,0 +1,84 @@
+package util
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newTestRateLimitedLog() (rl *RateLimitedLog, hook *test.Hook, clock *time.Time) {
+	logger, hook := test.NewNullLogger()
+	now := time.Unix(0, 0)
+	clock = &now
+	rl = NewRateLimitedLog(time.Minute)
+	rl.Logger = logger
+	rl.now = func() time.Time { return *clock }
+	return
+}
+
+func TestRateLimitedLogCollapsesRepeatedWarnings(t *testing.T) {
+	assert := assert.New(t)
+
+	rl, hook, clock := newTestRateLimitedLog()
+	for i := 0; i < 10; i++ {
+		rl.Warn(log.Fields{"at": "(Certificate) Length", "reason": "too short", "len": i}, "certificate format warning")
+		*clock = clock.Add(time.Second)
+	}
+	assert.Equal(1, len(hook.AllEntries()))
+
+	*clock = clock.Add(time.Minute)
+	rl.Warn(log.Fields{"at": "(Certificate) Length", "reason": "too short"}, "certificate format warning")
+	entries := hook.AllEntries()
+	assert.Equal(3, len(entries))
+	assert.Equal(9, entries[1].Data["suppressed"])
+	assert.Equal("(Certificate) Length", entries[1].Data["at"])
+	assert.Equal("certificate format warning", entries[2].Message)
+}
+
+func TestRateLimitedLogKeepsDifferentWarningsApart(t *testing.T) {
+	assert := assert.New(t)
+
+	rl, hook, _ := newTestRateLimitedLog()
+	rl.Warn(log.Fields{"at": "(Certificate) Length", "reason": "too short"}, "certificate format warning")
+	rl.Warn(log.Fields{"at": "(Certificate) Length", "reason": "too long"}, "certificate format warning")
+	rl.Warn(log.Fields{"at": "(Certificate) Length", "reason": "too short"}, "certificate format warning")
+	assert.Equal(2, len(hook.AllEntries()))
+}
+
+func TestRateLimitedLogFlushSummarizes(t *testing.T) {
+	assert := assert.New(t)
+
+	rl, hook, _ := newTestRateLimitedLog()
+	rl.Warn(log.Fields{"reason": "bad"}, "warning")
+	rl.Warn(log.Fields{"reason": "bad"}, "warning")
+	rl.Warn(log.Fields{"reason": "bad"}, "warning")
+	rl.Flush()
+	entries := hook.AllEntries()
+	assert.Equal(2, len(entries))
+	assert.Equal(2, entries[1].Data["suppressed"])
+	assert.Equal(`2 more "warning" warnings suppressed`, entries[1].Message)
+
+	rl.Flush()
+	assert.Equal(2, len(hook.AllEntries()), "nothing left to summarize")
+}
+
+func TestRateLimitedLogExpireSummarizesEndedBursts(t *testing.T) {
+	assert := assert.New(t)
+
+	rl, hook, clock := newTestRateLimitedLog()
+	rl.Warn(log.Fields{"reason": "bad"}, "warning")
+	rl.Warn(log.Fields{"reason": "bad"}, "warning")
+	assert.Equal(0, rl.Expire(clock.Add(time.Second)), "burst is still within the interval")
+
+	reaper := NewReaper()
+	reaper.Register("warnings", rl)
+	assert.Equal(map[string]int{"warnings": 1}, reaper.Reap(clock.Add(time.Minute)))
+	entries := hook.AllEntries()
+	assert.Equal(2, len(entries))
+	assert.Equal(1, entries[1].Data["suppressed"])
+
+	rl.Warn(log.Fields{"reason": "bad"}, "warning")
+	assert.Equal(3, len(hook.AllEntries()), "warning should be logged again once its burst was expired")
+}
@@ -0,0 +1,87 @@
+package util
+
+import (
+	log "github.com/sirupsen/logrus"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// a store whose entries expire
+type Expirer interface {
+	// remove every entry that expired before now, returning how many were removed
+	Expire(now time.Time) int
+}
+
+// Reaper drives expiry for every registered store from a single timer instead of one
+// goroutine per store, and keeps count of what it evicted from each
+type Reaper struct {
+	// the time Run passes to Reap, the system clock by default
+	Clock   Clock
+	access  sync.Mutex
+	names   []string
+	stores  map[string]Expirer
+	evicted map[string]int
+}
+
+// create a Reaper with no stores registered
+func NewReaper() (reaper *Reaper) {
+	reaper = &Reaper{
+		Clock:   SystemClock,
+		stores:  make(map[string]Expirer),
+		evicted: make(map[string]int),
+	}
+	return
+}
+
+// register a store to be expired under name, replacing any store with that name
+func (reaper *Reaper) Register(name string, store Expirer) {
+	reaper.access.Lock()
+	if _, ok := reaper.stores[name]; !ok {
+		reaper.names = append(reaper.names, name)
+	}
+	reaper.stores[name] = store
+	reaper.access.Unlock()
+}
+
+// expire every registered store once, in the order they were registered
+// returns how many entries were evicted from each store in this pass
+func (reaper *Reaper) Reap(now time.Time) (evicted map[string]int) {
+	evicted = make(map[string]int)
+	reaper.access.Lock()
+	defer reaper.access.Unlock()
+	for _, name := range reaper.names {
+		count := reaper.stores[name].Expire(now)
+		evicted[name] = count
+		reaper.evicted[name] += count
+	}
+	log.WithFields(log.Fields{
+		"at":      "(Reaper) Reap",
+		"evicted": evicted,
+	}).Debug("expired entries")
+	return
+}
+
+// return how many entries have been evicted from the store registered under name
+func (reaper *Reaper) Evicted(name string) (count int) {
+	reaper.access.Lock()
+	count = reaper.evicted[name]
+	reaper.access.Unlock()
+	return
+}
+
+// call Reap every interval plus up to jitter until stop is closed
+func (reaper *Reaper) Run(interval, jitter time.Duration, stop chan struct{}) {
+	for {
+		wait := interval
+		if jitter > 0 {
+			wait += time.Duration(rand.Int63n(int64(jitter)))
+		}
+		select {
+		case <-stop:
+			return
+		case <-time.After(wait):
+			reaper.Reap(reaper.Clock.Now())
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// a store of expiry times
+type expiringStore map[string]time.Time
+
+func (store expiringStore) Expire(now time.Time) (count int) {
+	for key, expires := range store {
+		if expires.Before(now) {
+			delete(store, key)
+			count++
+		}
+	}
+	return
+}
+
+func TestReaperEvictsFromEveryStoreInOnePass(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Unix(1000, 0)
+	leases := expiringStore{
+		"old":   now.Add(-time.Minute),
+		"fresh": now.Add(time.Minute),
+	}
+	tags := expiringStore{
+		"old1": now.Add(-time.Second),
+		"old2": now.Add(-time.Hour),
+	}
+	reaper := NewReaper()
+	reaper.Register("leases", leases)
+	reaper.Register("tags", tags)
+
+	evicted := reaper.Reap(now)
+	assert.Equal(map[string]int{"leases": 1, "tags": 2}, evicted)
+	assert.Equal(expiringStore{"fresh": now.Add(time.Minute)}, leases)
+	assert.Equal(0, len(tags))
+
+	evicted = reaper.Reap(now.Add(2 * time.Minute))
+	assert.Equal(map[string]int{"leases": 1, "tags": 0}, evicted)
+	assert.Equal(2, reaper.Evicted("leases"))
+	assert.Equal(2, reaper.Evicted("tags"))
+}
+
+func TestReaperRunStops(t *testing.T) {
+	assert := assert.New(t)
+
+	store := expiringStore{"old": time.Unix(0, 0)}
+	reaper := NewReaper()
+	reaper.Register("store", store)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		reaper.Run(time.Millisecond, time.Millisecond, stop)
+		close(done)
+	}()
+	for reaper.Evicted("store") == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(stop)
+	<-done
+	assert.Equal(1, reaper.Evicted("store"))
+}
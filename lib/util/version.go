@@ -0,0 +1,48 @@
+package util
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ERR_VERSION_NOT_NUMERIC = errors.New("version is not a dotted number")
+
+// a version split into its numeric parts so versions can be compared
+// i.e. a router.version such as 0.9.50, or the unix timestamp reseed su3s use as their version
+type Version []int
+
+// parse a version string such as "0.9.50" or "1622435443"
+// every part must be a non-negative number, so "" and "0.9.50-rc1" are rejected
+func ParseVersion(str string) (version Version, err error) {
+	for _, part := range strings.Split(str, ".") {
+		var number int
+		number, err = strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return nil, ERR_VERSION_NOT_NUMERIC
+		}
+		version = append(version, number)
+	}
+	return
+}
+
+// return -1, 0 or 1 if version is older than, the same as or newer than other
+// missing trailing parts count as 0, so 0.9 and 0.9.0 are the same version
+func (version Version) Compare(other Version) int {
+	for i := 0; i < len(version) || i < len(other); i++ {
+		a, b := 0, 0
+		if i < len(version) {
+			a = version[i]
+		}
+		if i < len(other) {
+			b = other[i]
+		}
+		if a < b {
+			return -1
+		}
+		if a > b {
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVersionCompare(t *testing.T) {
+	assert := assert.New(t)
+
+	parse := func(str string) Version {
+		version, err := ParseVersion(str)
+		assert.Nil(err, str)
+		return version
+	}
+	assert.Equal(0, parse("0.9.51").Compare(parse("0.9.51")))
+	assert.Equal(-1, parse("0.9.9").Compare(parse("0.9.51")))
+	assert.Equal(1, parse("1.5.0").Compare(parse("0.9.51")))
+	assert.Equal(-1, parse("0.9.49").Compare(parse("0.9.50")))
+	assert.Equal(1, parse("0.10").Compare(parse("0.9.50")))
+	assert.Equal(0, parse("0.9").Compare(parse("0.9.0")))
+	assert.Equal(1, parse("1622435443").Compare(parse("1622435000")))
+}
+
+func TestParseVersionRejectsNonNumericVersions(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, str := range []string{"", "0.9.50-rc1", "0..9", "0.-1"} {
+		_, err := ParseVersion(str)
+		assert.Equal(ERR_VERSION_NOT_NUMERIC, err, str)
+	}
+}
@@ -4,9 +4,17 @@ import (
 	"github.com/go-i2p/go-i2p/lib/router"
 	"github.com/go-i2p/go-i2p/lib/util/signals"
 	log "github.com/sirupsen/logrus"
+	"os"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "addr" {
+		if err := addrCommand(os.Args[2:], os.Stdout); err != nil {
+			log.Error(err)
+			os.Exit(1)
+		}
+		return
+	}
 	go signals.Handle()
 	log.Info("parsing i2p router configuration")
 